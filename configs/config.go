@@ -1,16 +1,11 @@
 package configs
 
 import (
-	"booking/internal/pkg/glog"
-	"context"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-
 	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -54,7 +49,7 @@ func New(path, state string) (*Configs, error) {
 	vn.OnConfigChange(func(e fsnotify.Event) {
 		log.Printf("config file changed: %v", e.Name)
 		if err := conf.binding(vn); err != nil {
-			log.Printf("binding error:", err)
+			log.Printf("binding error: %v", err)
 		}
 		log.Printf("config: %+v", conf)
 	})
@@ -63,7 +58,7 @@ func New(path, state string) (*Configs, error) {
 
 func (c *Configs) binding(v *viper.Viper) error {
 	if err := v.Unmarshal(&c); err != nil {
-		log.Printf("failed to unmarshal config: ", err)
+		log.Printf("failed to unmarshal config: %v", err)
 		return err
 	}
 	return nil
@@ -74,54 +69,218 @@ type (
 		Stage      Stage
 		HTTPServer HTTPServer `mapstructure:"http_server"`
 		Database   struct {
-			Type  string  `mapstructure:"type"`
-			Mongo MongoDB `mapstructure:"mongo"`
+			Type   string  `mapstructure:"type"`
+			Mongo  MongoDB `mapstructure:"mongo"`
+			Memory Memory  `mapstructure:"memory"`
+			SQL    SQL     `mapstructure:"sql"`
 		} `mapstructure:"database"`
-		Jwt struct {
-			Duration time.Duration `mapstructure:"duration"`
-		} `mapstructure:"jwt"`
+		Jwt             Jwt             `mapstructure:"jwt"`
+		Reservation     Reservation     `mapstructure:"reservation"`
+		Mail            Mail            `mapstructure:"mail"`
+		MemberToken     MemberToken     `mapstructure:"member_token"`
+		LoginProtection LoginProtection `mapstructure:"login_protection"`
+		TwoFactor       TwoFactor       `mapstructure:"two_factor"`
+		Tracing         Tracing         `mapstructure:"tracing"`
+		RateLimit       RateLimit       `mapstructure:"rate_limit"`
 	}
 
-	// Config hold MongoDB configuration information
-	MongoDB struct {
-		Address  string        `envconfig:"MONGODB_ADDRS" mapstructure:"address"`
-		Database string        `envconfig:"MONGODB_DATABASE" default:"booking" mapstructure:"database"`
-		Username string        `mapstructure:"username"`
-		Password string        `mapstructure:"password"`
-		Timeout  time.Duration `mapstructure:"timout"`
+	// RateLimit hold the token bucket policies and the routes they apply to, Store is memory.
+	// APIKeyHeader is the header read by policies keyed by api_key, X-API-Key by default,
+	// APIKeys the SHA-256 hex digests of the accepted keys
+	RateLimit struct {
+		Store        string                     `mapstructure:"store"`
+		APIKeyHeader string                     `mapstructure:"api_key_header"`
+		APIKeys      []string                   `mapstructure:"api_keys"`
+		Policies     map[string]RateLimitPolicy `mapstructure:"policies"`
+		Routes       []RateLimitRoute           `mapstructure:"routes"`
 	}
 
-	HTTPServer struct {
-		Address           string        `mapstructure:"address"`
-		Port              int           `mapstructure:"port"`
-		ReadTimeout       time.Duration `mapstructure:"read_timeout"`
-		WriteTimeout      time.Duration `mapstructure:"write_timeout"`
-		ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
-		ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	// RateLimitRoute apply the named policy, which must be configured, to the route of Path,
+	// or to the routes starting with Path when it ends with *. The longest matching path wins
+	RateLimitRoute struct {
+		Path   string `mapstructure:"path"`
+		Policy string `mapstructure:"policy"`
 	}
-)
 
-// Dial dial to target server with Monotonic mode
-func Dial(conf *MongoDB, logger glog.Logger) (*mongo.Client, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
-	// Set client options
-	clientOptions := options.Client().ApplyURI(fmt.Sprintf("mongodb+srv://%s", conf.Address))
-	// Connect to MongoDB
-	client, err := mongo.Connect(ctx, clientOptions)
-	if err != nil {
-		logger.Errorf("Got an error: %v", err)
+	// RateLimitPolicy allow Burst requests at once, refilled with Limit requests every Period,
+	// per client IP, member or API key by Key. Burst is Limit by default
+	RateLimitPolicy struct {
+		Key    string        `mapstructure:"key"`
+		Limit  int           `mapstructure:"limit"`
+		Period time.Duration `mapstructure:"period"`
+		Burst  int           `mapstructure:"burst"`
 	}
 
-	// Check the connection
-	err = client.Ping(ctx, nil)
+	// Tracing hold the span exporter, Exporter is none, stdout, file or otlp
+	Tracing struct {
+		Exporter    string `mapstructure:"exporter"`
+		ServiceName string `mapstructure:"service_name"`
+		File        string `mapstructure:"file"`
+		// OTLP send spans to an OpenTelemetry collector over HTTP, e.g. http://localhost:4318
+		OTLP struct {
+			Endpoint  string            `mapstructure:"endpoint"`
+			Headers   map[string]string `mapstructure:"headers"`
+			Timeout   time.Duration     `mapstructure:"timeout"`
+			BatchSize int               `mapstructure:"batch_size"`
+			Interval  time.Duration     `mapstructure:"interval"`
+		} `mapstructure:"otlp"`
+	}
 
-	if err != nil {
-		log.Fatal(err)
+	// TwoFactor hold the TOTP settings, Issuer is the name shown by authenticator apps and
+	// Skew the number of 30s steps a code is still accepted before or after its time
+	TwoFactor struct {
+		Issuer        string `mapstructure:"issuer"`
+		Skew          int    `mapstructure:"skew"`
+		RecoveryCodes int    `mapstructure:"recovery_codes"`
 	}
 
-	fmt.Println("Connected to MongoDB!")
+	// LoginProtection hold the limits of failed logins per account and per client IP
+	LoginProtection struct {
+		Account Throttle `mapstructure:"account"`
+		IP      Throttle `mapstructure:"ip"`
+	}
 
-	logger.Infof("successfully dialing to MongoDB at %v", conf.Address)
-	return client, nil
-}
+	// Throttle hold the delays applied after failed attempts. The delay doubles from BaseDelay
+	// up to MaxDelay on each failure and MaxAttempts failures lock for Lockout, failures are
+	// forgotten after Window without any
+	Throttle struct {
+		MaxAttempts int           `mapstructure:"max_attempts"`
+		Window      time.Duration `mapstructure:"window"`
+		BaseDelay   time.Duration `mapstructure:"base_delay"`
+		MaxDelay    time.Duration `mapstructure:"max_delay"`
+		Lockout     time.Duration `mapstructure:"lockout"`
+	}
+
+	// Mail hold the mail delivery settings, Type is smtp, file or memory.
+	// BaseURL is the public URL of the API used to build links sent by mail
+	Mail struct {
+		Type    string `mapstructure:"type"`
+		From    string `mapstructure:"from"`
+		BaseURL string `mapstructure:"base_url"`
+		FileDir string `mapstructure:"file_dir"`
+		SMTP    struct {
+			Host     string `mapstructure:"host"`
+			Port     int    `mapstructure:"port"`
+			Username string `mapstructure:"username"`
+			Password string `mapstructure:"password"`
+		} `mapstructure:"smtp"`
+	}
+
+	// MemberToken hold the lifetime of the single-use tokens sent to members by mail
+	MemberToken struct {
+		VerifyEmailDuration    time.Duration `mapstructure:"verify_email_duration"`
+		ResetPasswordDuration  time.Duration `mapstructure:"reset_password_duration"`
+		LoginChallengeDuration time.Duration `mapstructure:"login_challenge_duration"`
+	}
+
+	// Jwt hold token lifetimes and the keys signing and verifying tokens,
+	// SigningKey is the id of the key signing new tokens
+	Jwt struct {
+		Duration        time.Duration `mapstructure:"duration"`
+		RefreshDuration time.Duration `mapstructure:"refresh_duration"`
+		SigningKey      string        `mapstructure:"signing_key"`
+		Keys            []JwtKey      `mapstructure:"keys"`
+	}
+
+	// JwtKey hold a key used for tokens. HMAC algorithms (HS256...) use Secret, overridden by
+	// the environment variable named SecretEnv when set. RSA (RS256...) and ECDSA (ES256...)
+	// algorithms use PEM files, a key without private key file only verifies tokens.
+	JwtKey struct {
+		ID             string `mapstructure:"id"`
+		Algorithm      string `mapstructure:"algorithm"`
+		Secret         string `mapstructure:"secret"`
+		SecretEnv      string `mapstructure:"secret_env"`
+		PrivateKeyFile string `mapstructure:"private_key_file"`
+		PublicKeyFile  string `mapstructure:"public_key_file"`
+	}
+
+	// Reservation hold opening hours and time slot settings used to search availability,
+	// open and close times are offsets from midnight
+	Reservation struct {
+		OpenTime        time.Duration `mapstructure:"open_time"`
+		CloseTime       time.Duration `mapstructure:"close_time"`
+		SlotInterval    time.Duration `mapstructure:"slot_interval"`
+		DefaultDuration time.Duration `mapstructure:"default_duration"`
+	}
+
+	// Memory hold the in-memory database settings, the owner is created on start
+	// when an email is set so a fresh database can be used
+	Memory struct {
+		OwnerEmail    string `mapstructure:"owner_email"`
+		OwnerPassword string `mapstructure:"owner_password"`
+	}
+
+	// SQL hold the settings of MySQL, PostgreSQL and SQLite databases. MySQL DSNs
+	// need parseTime=true, SQLite always uses a single connection
+	SQL struct {
+		DSN             string        `mapstructure:"dsn"`
+		MaxOpenConns    int           `mapstructure:"max_open_conns"`
+		MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+		ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	}
+
+	// Config hold MongoDB configuration information. Address is a mongodb:// or mongodb+srv:// URI,
+	// an address without scheme is dialed as mongodb+srv. Collections rename collections by their
+	// default name, e.g. members: users, and CollectionPrefix is prepended to every collection name
+	MongoDB struct {
+		Address          string            `envconfig:"MONGODB_ADDRS" mapstructure:"address"`
+		Database         string            `envconfig:"MONGODB_DATABASE" mapstructure:"database"`
+		CollectionPrefix string            `envconfig:"MONGODB_COLLECTION_PREFIX" mapstructure:"collection_prefix"`
+		Collections      map[string]string `ignored:"true" mapstructure:"collections"`
+		// Username and Password authenticate against AuthSource, the database of the URI
+		// or admin by default, and override the credentials of the URI
+		Username   string `envconfig:"MONGODB_USERNAME" mapstructure:"username"`
+		Password   string `envconfig:"MONGODB_PASSWORD" mapstructure:"password"`
+		AuthSource string `mapstructure:"auth_source"`
+		// Timeout bound connecting, selecting a server and health check pings
+		Timeout         time.Duration `mapstructure:"timeout"`
+		MaxPoolSize     uint64        `mapstructure:"max_pool_size"`
+		MinPoolSize     uint64        `mapstructure:"min_pool_size"`
+		MaxConnIdleTime time.Duration `mapstructure:"max_conn_idle_time"`
+		// AutoMigrate apply pending migrations on start instead of only reporting them
+		AutoMigrate bool `mapstructure:"auto_migrate"`
+	}
+
+	HTTPServer struct {
+		Address           string          `mapstructure:"address"`
+		Port              int             `mapstructure:"port"`
+		ReadTimeout       time.Duration   `mapstructure:"read_timeout"`
+		WriteTimeout      time.Duration   `mapstructure:"write_timeout"`
+		ReadHeaderTimeout time.Duration   `mapstructure:"read_header_timeout"`
+		ShutdownTimeout   time.Duration   `mapstructure:"shutdown_timeout"`
+		CORS              CORS            `mapstructure:"cors"`
+		SecurityHeaders   SecurityHeaders `mapstructure:"security_headers"`
+		// TrustedProxies are the IP addresses or CIDR networks of the proxies in front of the server,
+		// the client IP is only read from X-Forwarded-For and X-Real-IP of requests they send
+		TrustedProxies []string `mapstructure:"trusted_proxies"`
+		// ShutdownDrain is how long the server keeps serving once /readiness turns 503 on shutdown,
+		// so load balancers stop sending traffic before the listener is closed
+		ShutdownDrain time.Duration `mapstructure:"shutdown_drain"`
+	}
+
+	// CORS hold the cross-origin requests allowed, none when AllowedOrigins is empty. Origins are
+	// exact, * or have a wildcard subdomain, e.g. https://*.booking.com. MaxAge is how long browsers
+	// cache preflight responses
+	CORS struct {
+		AllowedOrigins   []string      `mapstructure:"allowed_origins"`
+		AllowedMethods   []string      `mapstructure:"allowed_methods"`
+		AllowedHeaders   []string      `mapstructure:"allowed_headers"`
+		ExposedHeaders   []string      `mapstructure:"exposed_headers"`
+		AllowCredentials bool          `mapstructure:"allow_credentials"`
+		MaxAge           time.Duration `mapstructure:"max_age"`
+	}
+
+	// SecurityHeaders hold the security headers of every response, empty values are not sent
+	SecurityHeaders struct {
+		// HSTS is sent when MaxAge is set, browsers only honor it over HTTPS
+		HSTS struct {
+			MaxAge            time.Duration `mapstructure:"max_age"`
+			IncludeSubdomains bool          `mapstructure:"include_subdomains"`
+			Preload           bool          `mapstructure:"preload"`
+		} `mapstructure:"hsts"`
+		ContentTypeNosniff    bool   `mapstructure:"content_type_nosniff"`
+		FrameOptions          string `mapstructure:"frame_options"`
+		ContentSecurityPolicy string `mapstructure:"content_security_policy"`
+		ReferrerPolicy        string `mapstructure:"referrer_policy"`
+	}
+)
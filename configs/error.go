@@ -4,9 +4,15 @@ import (
 	"booking/internal/pkg/utils"
 	"fmt"
 	"log"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -15,11 +21,21 @@ type ErrorCode struct {
 	Message string `json:"message"`
 }
 
+// DefaultLanguage is the language of the error messages answered when no other is configured
+const DefaultLanguage = "en"
+
 type ErrorMessage struct {
-	vn         *viper.Viper
+	vn *viper.Viper
+	// fallback hold the messages missing in vn, the default language ones
+	fallback   *ErrorMessage
+	catalogs   *catalogs
 	ConfigPath string
-	Success    ErrorCode
-	Database   struct {
+	// DefaultLanguage is the language answered to clients accepting none of the others, en if empty
+	DefaultLanguage string
+	// Language of the messages
+	Language string
+	Success  ErrorCode
+	Database struct {
 		Database     ErrorCode
 		DataNotFound ErrorCode
 	}
@@ -29,31 +45,185 @@ type ErrorMessage struct {
 		EmailExists            ErrorCode
 		FailedAuthentication   ErrorCode
 		ValidationFailed       ErrorCode
+		PermissionDenied       ErrorCode
+		EmailNotVerified       ErrorCode
+		InvalidToken           ErrorCode
+		InvalidTwoFactorCode   ErrorCode
+		TwoFactorEnabled       ErrorCode
+		TwoFactorNotEnrolled   ErrorCode
+		PartyTooLarge          ErrorCode
+	}
+	Conflict struct {
+		ReservationOverlap ErrorCode
+		DataExists         ErrorCode
+	}
+	TooManyRequests struct {
+		AccountLocked ErrorCode
+		LoginAttempts ErrorCode
+		RateLimited   ErrorCode
 	}
+	// Validation hold the messages of invalid fields by validate rule, e.g. required
+	Validation map[string]string
 }
 
-// Initialization error message
+// catalogs hold the error messages of every language, it is shared by the copies of ErrorMessage
+type catalogs struct {
+	mu     sync.RWMutex
+	vns    map[string]*viper.Viper
+	byLang map[string]*ErrorMessage
+}
+
+// Init load the error messages of every language from the errors.<language>.yml files of
+// ConfigPath, errors.yml holds the default language ones when its file is missing.
+// The default language messages are set to em
 func (em *ErrorMessage) Init() error {
 	log.Println("initialzing error messages")
-	vn := viper.New()
-	vn.AddConfigPath(em.ConfigPath)
-	vn.SetConfigName("errors")
+	if em.DefaultLanguage == "" {
+		em.DefaultLanguage = DefaultLanguage
+	}
 
-	if err := vn.ReadInConfig(); err != nil {
+	files, err := filepath.Glob(filepath.Join(em.ConfigPath, "errors.*.yml"))
+	if err != nil {
 		return err
 	}
-	em.vn = vn
+	vns := map[string]*viper.Viper{}
+	for _, file := range files {
+		vn := viper.New()
+		vn.SetConfigFile(file)
+		if err := vn.ReadInConfig(); err != nil {
+			return errors.Wrapf(err, "failed to read %s", file)
+		}
+		vns[strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "errors."), ".yml"))] = vn
+	}
+	if _, ok := vns[em.DefaultLanguage]; !ok {
+		vn := viper.New()
+		vn.AddConfigPath(em.ConfigPath)
+		vn.SetConfigName("errors")
+		if err := vn.ReadInConfig(); err != nil {
+			return errors.Wrapf(err, "no error messages of default language %s", em.DefaultLanguage)
+		}
+		vns[em.DefaultLanguage] = vn
+	}
+
+	em.catalogs = &catalogs{vns: vns}
+	em.build()
 
+	for _, vn := range vns {
+		vn.WatchConfig()
+		vn.OnConfigChange(func(e fsnotify.Event) {
+			log.Printf("error messages change: %s", e.Name)
+			em.build()
+		})
+	}
+
+	return nil
+}
+
+// build map the messages of every language, the default language ones are also set to em
+func (em *ErrorMessage) build() {
+	em.catalogs.mu.Lock()
+	defer em.catalogs.mu.Unlock()
+
+	def := em.catalog(em.DefaultLanguage, nil)
+	byLang := map[string]*ErrorMessage{em.DefaultLanguage: def}
+	for lang := range em.catalogs.vns {
+		if lang != em.DefaultLanguage {
+			byLang[lang] = em.catalog(lang, def)
+		}
+	}
+	em.catalogs.byLang = byLang
+
+	em.vn = def.vn
+	em.Language = def.Language
 	em.mapping("", reflect.ValueOf(em).Elem())
+	em.Validation = def.Validation
+}
+
+// catalog return the messages of a language, the missing ones are taken from fallback
+func (em *ErrorMessage) catalog(lang string, fallback *ErrorMessage) *ErrorMessage {
+	vn := em.catalogs.vns[lang]
+	c := &ErrorMessage{
+		vn:              vn,
+		fallback:        fallback,
+		catalogs:        em.catalogs,
+		ConfigPath:      em.ConfigPath,
+		DefaultLanguage: em.DefaultLanguage,
+		Language:        lang,
+		Validation:      map[string]string{},
+	}
+	c.mapping("", reflect.ValueOf(c).Elem())
+	if fallback != nil {
+		for rule, message := range fallback.Validation {
+			c.Validation[rule] = message
+		}
+	}
+	for rule, message := range vn.GetStringMapString("error.validation") {
+		c.Validation[rule] = message
+	}
+	return c
+}
+
+// Lang return the messages of the language preferred by an Accept-Language header value,
+// e.g. "vi-VN,vi;q=0.9,en;q=0.8", the default language ones when no other language is accepted
+func (em *ErrorMessage) Lang(acceptLanguage string) *ErrorMessage {
+	if em.catalogs == nil {
+		return em
+	}
+	em.catalogs.mu.RLock()
+	defer em.catalogs.mu.RUnlock()
+
+	for _, lang := range acceptedLanguages(acceptLanguage) {
+		if lang == "*" {
+			break
+		}
+		if c, ok := em.catalogs.byLang[lang]; ok {
+			return c
+		}
+		if i := strings.Index(lang, "-"); i > 0 {
+			if c, ok := em.catalogs.byLang[lang[:i]]; ok {
+				return c
+			}
+		}
+	}
+	return em.catalogs.byLang[em.DefaultLanguage]
+}
 
-	vn.WatchConfig()
-	vn.OnConfigChange(func(e fsnotify.Event) {
-		log.Println("error messages change: %s", e.Name)
-		em.vn = vn
-		em.mapping("", reflect.ValueOf(em).Elem())
+// acceptedLanguages return the lower case language tags of an Accept-Language header value
+// from the most to the least preferred, the refused ones with q=0 are left out
+func acceptedLanguages(acceptLanguage string) []string {
+	type accepted struct {
+		lang string
+		q    float64
+	}
+	var languages []accepted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		params := strings.Split(part, ";")
+		lang := strings.ToLower(strings.TrimSpace(params[0]))
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, _ = strconv.ParseFloat(param[2:], 64); q > 1 {
+					q = 0
+				}
+			}
+		}
+		if q > 0 {
+			languages = append(languages, accepted{lang, q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
 	})
 
-	return nil
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.lang
+	}
+	return tags
 }
 
 // mapping method is used to map the field name
@@ -67,7 +237,6 @@ func (em ErrorMessage) mapping(name string, v reflect.Value) {
 		fn := utils.Underscore(v.Type().Field(i).Name)
 		if name != "" {
 			fn = fmt.Sprint(name, ".", fn)
-			fmt.Println(fn)
 		}
 
 		if fi.Type().Name() == "ErrorCode" {
@@ -78,8 +247,19 @@ func (em ErrorMessage) mapping(name string, v reflect.Value) {
 	}
 }
 
-// ErrorCode method helps to get the value of error
+// ErrorCode method helps to get the value of error, codes are the default language ones
+// so they stay the same in every language
 func (em ErrorMessage) ErrorCode(name string) ErrorCode {
+	if em.vn == nil {
+		return ErrorCode{}
+	}
+	if em.fallback != nil {
+		rtn := em.fallback.ErrorCode(name)
+		if message := em.vn.GetString(fmt.Sprintf("error.%s.message", name)); message != "" {
+			rtn.Message = message
+		}
+		return rtn
+	}
 	rtn := ErrorCode{
 		Code:    em.vn.GetString(fmt.Sprintf("error.%s.code", name)),
 		Message: em.vn.GetString(fmt.Sprintf("error.%s.message", name)),
@@ -0,0 +1,126 @@
+package configs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	errorsEN = `error:
+  database:
+    data_not_found:
+      code: "203"
+      message: "Data not found."
+    database:
+      code: "103"
+      message: "Database error."
+  validation:
+    required: "{field} is required."
+    max: "{field} must be at most {param}."
+`
+	errorsVI = `error:
+  database:
+    data_not_found:
+      code: "999"
+      message: "Không tìm thấy dữ liệu."
+  validation:
+    required: "{field} là bắt buộc."
+`
+)
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newErrorMessage(t *testing.T, files map[string]string) *ErrorMessage {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		writeFile(t, filepath.Join(dir, name), content)
+	}
+	em := &ErrorMessage{ConfigPath: dir}
+	if err := em.Init(); err != nil {
+		t.Fatalf("Init() err = %v", err)
+	}
+	return em
+}
+
+func TestErrorMessageLang(t *testing.T) {
+	em := newErrorMessage(t, map[string]string{"errors.en.yml": errorsEN, "errors.vi.yml": errorsVI})
+	if em.Database.DataNotFound.Message != "Data not found." {
+		t.Errorf("default message = %q; expected the en one", em.Database.DataNotFound.Message)
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		lang           string
+	}{
+		{"", "en"},
+		{"vi", "vi"},
+		{"vi-VN,vi;q=0.9,en;q=0.8", "vi"},
+		{"VI-vn", "vi"},
+		{"en;q=0.5, vi;q=0.8", "vi"},
+		{"fr-FR, fr;q=0.9", "en"},
+		{"fr, vi;q=0.3", "vi"},
+		{"vi;q=0, en", "en"},
+		{"*, vi;q=0.5", "en"},
+		{"vi;q=abc", "en"},
+	}
+	for _, tt := range tests {
+		if lang := em.Lang(tt.acceptLanguage).Language; lang != tt.lang {
+			t.Errorf("Lang(%q) = %s; expected %s", tt.acceptLanguage, lang, tt.lang)
+		}
+	}
+
+	vi := em.Lang("vi")
+	if got, want := vi.Database.DataNotFound, (ErrorCode{Code: "203", Message: "Không tìm thấy dữ liệu."}); got != want {
+		t.Errorf("vi data not found = %+v; expected %+v, codes are the en ones", got, want)
+	}
+	if got, want := vi.Database.Database, em.Database.Database; got != want {
+		t.Errorf("vi database = %+v; expected the en one %+v", got, want)
+	}
+	if got := vi.ErrorCode("database.data_not_found").Message; got != "Không tìm thấy dữ liệu." {
+		t.Errorf("vi ErrorCode() message = %q", got)
+	}
+	if vi.Validation["required"] != "{field} là bắt buộc." || vi.Validation["max"] != "{field} must be at most {param}." {
+		t.Errorf("vi validation = %v; expected missing rules in en", vi.Validation)
+	}
+}
+
+func TestErrorMessageDefaultFile(t *testing.T) {
+	em := newErrorMessage(t, map[string]string{"errors.yml": errorsEN, "errors.vi.yml": errorsVI})
+	if em.Language != "en" || em.Database.DataNotFound.Code != "203" {
+		t.Errorf("default messages = %s %+v; expected errors.yml", em.Language, em.Database.DataNotFound)
+	}
+	if lang := em.Lang("vi").Language; lang != "vi" {
+		t.Errorf("Lang(vi) = %s", lang)
+	}
+
+	missing := &ErrorMessage{ConfigPath: t.TempDir(), DefaultLanguage: "vi"}
+	if err := missing.Init(); err == nil {
+		t.Errorf("Init() without default language messages succeeded")
+	}
+}
+
+func TestErrorMessageReload(t *testing.T) {
+	em := newErrorMessage(t, map[string]string{"errors.en.yml": errorsEN, "errors.vi.yml": errorsVI})
+	copied := *em
+
+	writeFile(t, filepath.Join(em.ConfigPath, "errors.vi.yml"), `error:
+  database:
+    data_not_found:
+      message: "Dữ liệu không tồn tại."
+`)
+	deadline := time.Now().Add(5 * time.Second)
+	for copied.Lang("vi").Database.DataNotFound.Message != "Dữ liệu không tồn tại." {
+		if time.Now().After(deadline) {
+			t.Fatalf("vi messages not reloaded, data not found = %+v", copied.Lang("vi").Database.DataNotFound)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,37 +2,67 @@ package api
 
 import (
 	"booking/configs"
+	"context"
 	"net/http"
+	"path/filepath"
+
+	sessionhandler "booking/internal/app/api/handler/session"
+	sessionRepository "booking/internal/app/repositories/session"
+	sessionServices "booking/internal/app/services/session"
 
 	memberhandler "booking/internal/app/api/handler/member"
 	memberServices "booking/internal/app/services/member"
 	memberRepository "booking/internal/app/repositories/member"
+	tokenRepository "booking/internal/app/repositories/token"
 
 	tablehandler "booking/internal/app/api/handler/table"
 	tableServices "booking/internal/app/services/table"
 	tableRepository "booking/internal/app/repositories/table"
 
+	reservationhandler "booking/internal/app/api/handler/reservation"
+	reservationServices "booking/internal/app/services/reservation"
+	reservationRepository "booking/internal/app/repositories/reservation"
+
+	restauranthandler "booking/internal/app/api/handler/restaurant"
+	restaurantServices "booking/internal/app/services/restaurant"
+	restaurantRepository "booking/internal/app/repositories/restaurant"
+
+	availabilityhandler "booking/internal/app/api/handler/availability"
+	availabilityServices "booking/internal/app/services/availability"
+
 	"booking/internal/app/db"
+	"booking/internal/app/migrations"
+	"booking/internal/app/types"
 
 	"booking/internal/pkg/glog"
 	"booking/internal/pkg/health"
+	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/mail"
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/ratelimit"
+	"booking/internal/pkg/trace"
 	"booking/internal/pkg/middleware"
+	"booking/internal/pkg/utils"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type (
 	// InfraConns holds infrastructure services connections like MongoDB, Redis, Kafka,...
 	InfraConns struct {
 		Databases db.Connections
+		// Tracing export the spans of requests, pending spans are flushed on Close
+		Tracing trace.Exporter
 	}
 
 	middlewareFunc = func(http.HandlerFunc, *configs.ErrorMessage) http.HandlerFunc
 	route          struct {
-		path        string
-		method      string
-		handler     http.HandlerFunc
+		path    string
+		method  string
+		handler http.HandlerFunc
+		// middlewares run in the given order, e.g. Auth must come before Role
 		middlewares []middlewareFunc
 	}
 )
@@ -44,108 +74,448 @@ const (
 	delete = http.MethodDelete
 )
 
-// Init init all handlers
-func Init(conns *configs.Configs, em configs.ErrorMessage) (http.Handler, error) {
+// Init init all handlers and open the connections of the configured database,
+// they must be closed with InfraConns.Close on shutdown
+func Init(conns *configs.Configs, em configs.ErrorMessage) (http.Handler, *InfraConns, error) {
 	logger := glog.New()
+	infra := &InfraConns{
+		Databases: db.Connections{Type: conns.Database.Type},
+	}
+	exporter, err := trace.New(conns.Tracing)
+	if err != nil {
+		return nil, nil, err
+	}
+	trace.SetExporter(exporter)
+	infra.Tracing = exporter
 
 	// declare variable to pointer repository class
 	var memberRepo memberServices.Repository
+	var sessionRepo sessionServices.Repository
+	var tokenRepo memberServices.TokenRepository
 	var tableRepo tableServices.Repository
+	var reservationRepo reservationServices.Repository
+	var restaurantRepo restaurantServices.Repository
 
 	switch conns.Database.Type {
 	case db.TypeMongoDB:
-		s, err := configs.Dial(&conns.Database.Mongo, logger)
+		s, err := db.ConnectMongo(context.Background(), conns.Database.Mongo, logger)
 		if err != nil {
-			logger.Panicf("failed to dial to target server, err: %v", err)
+			return nil, nil, err
+		}
+		infra.Databases.MongoDB = s
+		if err := checkMigrations(s, conns.Database.Mongo, logger.WithField("package", "migrations")); err != nil {
+			return nil, nil, err
 		}
 		memberRepo = memberRepository.NewMongoRepository(s)
+		sessionRepo = sessionRepository.NewMongoRepository(s)
+		tokenRepo = tokenRepository.NewMongoRepository(s)
 		tableRepo = tableRepository.NewMongoRepository(s)
+		reservationRepo = reservationRepository.NewMongoRepository(s)
+		restaurantRepo = restaurantRepository.NewMongoRepository(s)
+
+	case db.TypeMemory:
+		logger.Warnf("using in-memory database, data is lost on exit")
+		members := memberRepository.NewMemoryRepository()
+		if err := seedOwner(members, conns.Database.Memory); err != nil {
+			return nil, nil, err
+		}
+		memberRepo = members
+		sessionRepo = sessionRepository.NewMemoryRepository()
+		tokenRepo = tokenRepository.NewMemoryRepository()
+		tableRepo = tableRepository.NewMemoryRepository()
+		reservationRepo = reservationRepository.NewMemoryRepository()
+		restaurantRepo = restaurantRepository.NewMemoryRepository()
+
+	case db.TypeMySQL, db.TypePostgres, db.TypeSQLite:
+		conf := conns.Database.SQL
+		s, err := db.OpenSQL(conns.Database.Type, conf.DSN)
+		if err != nil {
+			return nil, nil, err
+		}
+		infra.Databases.SQL = s
+		if conf.MaxOpenConns > 0 && conns.Database.Type != db.TypeSQLite {
+			s.SetMaxOpenConns(conf.MaxOpenConns)
+		}
+		if conf.MaxIdleConns > 0 {
+			s.SetMaxIdleConns(conf.MaxIdleConns)
+		}
+		s.SetConnMaxLifetime(conf.ConnMaxLifetime)
+
+		members := memberRepository.NewSQLRepository(s)
+		sessions := sessionRepository.NewSQLRepository(s)
+		tokens := tokenRepository.NewSQLRepository(s)
+		tables := tableRepository.NewSQLRepository(s)
+		reservations := reservationRepository.NewSQLRepository(s)
+		restaurants := restaurantRepository.NewSQLRepository(s)
+		for _, repo := range []interface {
+			CreateTables(ctx context.Context) error
+		}{members, sessions, tokens, tables, reservations, restaurants} {
+			if err := repo.CreateTables(context.Background()); err != nil {
+				return nil, nil, err
+			}
+		}
+		memberRepo = members
+		sessionRepo = sessions
+		tokenRepo = tokens
+		tableRepo = tables
+		reservationRepo = reservations
+		restaurantRepo = restaurants
 
 	default:
 		panic("database type not supported: " + conns.Database.Type)
 	}
 
+	keys, err := jwt.NewKeySet(conns.Jwt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mailer, err := mail.New(conns.Mail)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sessionLogger := logger.WithField("package", "session")
+	sessionSrv := sessionServices.NewService(conns, &em, sessionRepo, memberRepo, keys, sessionLogger)
+	sessionHandler := sessionhandler.New(conns, &em, sessionSrv, sessionLogger)
+
 	memberLogger := logger.WithField("package", "member")
-	memberSrv := memberServices.NewService(conns, &em, memberRepo, memberLogger)
+	memberSrv := memberServices.NewService(conns, &em, memberRepo, tokenRepo, sessionSrv, mailer, memberLogger)
 	memberHandler := memberhandler.New(conns, &em, memberSrv, memberLogger)
 
 	tableLogger := logger.WithField("package", "table")
-	tableSrv := tableServices.NewService(conns, &em, tableRepo, tableLogger)
+	tableSrv := tableServices.NewService(conns, &em, tableRepo, restaurantRepo, tableLogger)
 	tableHandler := tablehandler.New(conns, &em, tableSrv, tableLogger)
 
+	restaurantLogger := logger.WithField("package", "restaurant")
+	restaurantSrv := restaurantServices.NewService(conns, &em, restaurantRepo, tableRepo, restaurantLogger)
+	restaurantHandler := restauranthandler.New(conns, &em, restaurantSrv, restaurantLogger)
+
+	reservationLogger := logger.WithField("package", "reservation")
+	reservationSrv := reservationServices.NewService(conns, &em, reservationRepo, tableRepo, restaurantRepo, reservationLogger)
+	reservationHandler := reservationhandler.New(conns, &em, reservationSrv, reservationLogger)
+
+	availabilityLogger := logger.WithField("package", "availability")
+	availabilitySrv := availabilityServices.NewService(conns, &em, restaurantRepo, tableRepo, reservationRepo, availabilityLogger)
+	availabilityHandler := availabilityhandler.New(conns, &em, availabilitySrv, availabilityLogger)
+
+	authMW := middleware.Auth(keys, sessionSrv)
+	limiter, err := ratelimit.New(conns.RateLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	checks := healthChecks(conns, infra)
+
 	routes := []route{
 		// infra
 		route{
 			path:    "/readiness",
 			method:  get,
-			handler: health.Readiness().ServeHTTP,
+			handler: health.Readiness(infra.Databases.Ping).ServeHTTP,
+		},
+		route{
+			path:    "/liveness",
+			method:  get,
+			handler: health.Liveness(health.Funcs(localChecks(conns))...).ServeHTTP,
+		},
+		route{
+			path:    "/metrics",
+			method:  get,
+			handler: metrics.Handler().ServeHTTP,
+		},
+		route{
+			path:    "/health",
+			method:  get,
+			handler: health.Handler(checks...).ServeHTTP,
+		},
+		route{
+			path:    "/.well-known/jwks.json",
+			method:  get,
+			handler: keys.Handler().ServeHTTP,
 		},
 		// services
 		// api member
 		route{
 			path:        "/api/v1/member/{id:[a-z0-9-\\-]+}",
 			method:      get,
-			middlewares: []middlewareFunc{middleware.Auth},
+			middlewares: []middlewareFunc{authMW},
 			handler:     memberHandler.Get,
 		},
 		route{
 			path:        "/api/v1/member",
 			method:      post,
-			middlewares: []middlewareFunc{middleware.Auth},
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleManager)},
 			handler:     memberHandler.InsertMember,
 		},
 		route{
 			path:        "/api/v1/member",
 			method:      put,
-			middlewares: []middlewareFunc{middleware.Auth},
+			middlewares: []middlewareFunc{authMW},
 			handler:     memberHandler.UpdateMemberByID,
 		},
+		route{
+			path:        "/api/v1/member/{id:[a-z0-9-\\-]+}/unlock",
+			method:      put,
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleManager)},
+			handler:     memberHandler.Unlock,
+		},
+		// api restaurant
+		route{
+			path:        "/api/v1/restaurant/{id:[a-z0-9-\\-]+}",
+			method:      get,
+			middlewares: []middlewareFunc{authMW},
+			handler:     restaurantHandler.Get,
+		},
+		route{
+			path:        "/api/v1/restaurant/{id:[a-z0-9-\\-]+}/tables",
+			method:      get,
+			middlewares: []middlewareFunc{authMW},
+			handler:     restaurantHandler.FindTables,
+		},
+		route{
+			path:        "/api/v1/restaurant",
+			method:      get,
+			middlewares: []middlewareFunc{authMW},
+			handler:     restaurantHandler.FindAll,
+		},
+		route{
+			path:        "/api/v1/restaurant",
+			method:      post,
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleOwner)},
+			handler:     restaurantHandler.InsertRestaurant,
+		},
+		route{
+			path:        "/api/v1/restaurant",
+			method:      put,
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleOwner)},
+			handler:     restaurantHandler.UpdateRestaurantByID,
+		},
+		route{
+			path:        "/api/v1/restaurant-delete",
+			method:      put,
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleOwner)},
+			handler:     restaurantHandler.DeleteRestaurant,
+		},
 		// api table
 		route{
 			path:        "/api/v1/table",
 			method:      post,
-			middlewares: []middlewareFunc{middleware.Auth},
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleManager)},
 			handler:     tableHandler.InsertTable,
 		},
 		route{
 			path:        "/api/v1/table",
 			method:      put,
-			middlewares: []middlewareFunc{middleware.Auth},
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleStaff)},
 			handler:     tableHandler.UpdateTableByID,
 		},
 		route{
 			path:        "/api/v1/table-delete",
 			method:      put,
-			middlewares: []middlewareFunc{middleware.Auth},
+			middlewares: []middlewareFunc{authMW, middleware.Role(types.RoleManager)},
 			handler:     tableHandler.DeleteTable,
 		},
+		// api reservation
+		route{
+			path:        "/api/v1/reservation/{id:[a-z0-9-\\-]+}",
+			method:      get,
+			middlewares: []middlewareFunc{authMW},
+			handler:     reservationHandler.Get,
+		},
+		route{
+			path:        "/api/v1/reservation",
+			method:      get,
+			middlewares: []middlewareFunc{authMW},
+			handler:     reservationHandler.Find,
+		},
+		route{
+			path:        "/api/v1/reservation",
+			method:      post,
+			middlewares: []middlewareFunc{authMW},
+			handler:     reservationHandler.InsertReservation,
+		},
+		route{
+			path:        "/api/v1/reservation",
+			method:      put,
+			middlewares: []middlewareFunc{authMW},
+			handler:     reservationHandler.UpdateReservation,
+		},
+		route{
+			path:        "/api/v1/reservation/{id:[a-z0-9-\\-]+}",
+			method:      delete,
+			middlewares: []middlewareFunc{authMW},
+			handler:     reservationHandler.DeleteReservation,
+		},
+		// api availability
+		route{
+			path:        "/api/v1/availability",
+			method:      get,
+			middlewares: []middlewareFunc{authMW},
+			handler:     availabilityHandler.Search,
+		},
 		// api login
 		route{
 			path:    "/login",
 			method:  post,
 			handler: memberHandler.Login,
 		},
+		// api sign up
+		route{
+			path:    "/signup",
+			method:  post,
+			handler: memberHandler.SignUp,
+		},
+		route{
+			path:    "/verify",
+			method:  get,
+			handler: memberHandler.Verify,
+		},
+		// api session
+		route{
+			path:    "/auth/refresh",
+			method:  post,
+			handler: sessionHandler.Refresh,
+		},
+		route{
+			path:    "/auth/forgot-password",
+			method:  post,
+			handler: memberHandler.ForgotPassword,
+		},
+		route{
+			path:    "/auth/reset-password",
+			method:  post,
+			handler: memberHandler.ResetPassword,
+		},
+		// api two-factor authentication
+		route{
+			path:    "/auth/2fa/login",
+			method:  post,
+			handler: memberHandler.LoginTwoFactor,
+		},
+		route{
+			path:        "/auth/2fa/enroll",
+			method:      post,
+			middlewares: []middlewareFunc{authMW},
+			handler:     memberHandler.EnrollTwoFactor,
+		},
+		route{
+			path:        "/auth/2fa/confirm",
+			method:      post,
+			middlewares: []middlewareFunc{authMW},
+			handler:     memberHandler.ConfirmTwoFactor,
+		},
+		route{
+			path:        "/auth/2fa/disable",
+			method:      post,
+			middlewares: []middlewareFunc{authMW},
+			handler:     memberHandler.DisableTwoFactor,
+		},
+		route{
+			path:        "/auth/logout",
+			method:      post,
+			middlewares: []middlewareFunc{authMW},
+			handler:     sessionHandler.Logout,
+		},
+	}
+
+	proxies, err := utils.ParseTrustedProxies(conns.HTTPServer.TrustedProxies)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	loggingMW := middleware.Logging(logger.WithField("package", "middleware"))
 	r := mux.NewRouter()
 	r.Use(middleware.RequestID)
+	r.Use(middleware.ClientIP(proxies))
 	r.Use(middleware.StatusResponseWriter)
+	r.Use(middleware.Tracing)
+	r.Use(middleware.Metrics)
 	r.Use(loggingMW)
 	r.Use(handlers.CompressHandler)
 
 	for _, rt := range routes {
 		h := rt.handler
-		for _, mdw := range rt.middlewares {
-			h = mdw(h, &em)
+		for i := len(rt.middlewares) - 1; i >= 0; i-- {
+			h = rt.middlewares[i](h, &em)
+		}
+		// the policy of rate_limit.routes runs first, before auth
+		if policy, ok := limiter.RoutePolicy(rt.path); ok {
+			h = middleware.RateLimit(limiter, policy, keys)(h, &em)
 		}
 		r.Path(rt.path).Methods(rt.method).HandlerFunc(h)
 	}
 
-	return r, nil
+	// CORS wrap the router, preflight OPTIONS requests match no route
+	corsMW, err := middleware.CORS(conns.HTTPServer.CORS)
+	if err != nil {
+		return nil, nil, err
+	}
+	return middleware.SecurityHeaders(conns.HTTPServer.SecurityHeaders)(corsMW(r)), infra, nil
+}
+
+// healthChecks return the checks of the open database and of the directories written to
+func healthChecks(conf *configs.Configs, infra *InfraConns) []health.Check {
+	return append([]health.Check{{Name: "database", Check: infra.Databases.Ping}}, localChecks(conf)...)
+}
+
+// localChecks return the checks of the directories written to, they don't depend on other
+// services so a failure means restarting the server may help, unlike a database outage
+func localChecks(conf *configs.Configs) []health.Check {
+	var checks []health.Check
+	if file := glog.OutputFile(); file != "" {
+		checks = append(checks, health.Check{Name: "log_file", Check: health.Writable(filepath.Dir(file))})
+	}
+	if conf.Mail.Type == mail.TypeFile {
+		checks = append(checks, health.Check{Name: "mail_dir", Check: health.Writable(conf.Mail.FileDir)})
+	}
+	return checks
+}
+
+// checkMigrations apply the pending migrations when auto_migrate is set,
+// otherwise they are only reported until `booking migrate up` is run
+func checkMigrations(database *db.Mongo, conf configs.MongoDB, logger glog.Logger) error {
+	migrator := migrations.New(database, logger)
+	if conf.AutoMigrate {
+		_, err := migrator.Up(context.Background(), 0)
+		return err
+	}
+
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, migration := range pending {
+		logger.Warnf("migration %d is pending: %s, run `booking migrate up`", migration.Version, migration.Description)
+	}
+	return nil
+}
+
+// seedOwner create the owner of an in-memory database so it can be used right away
+func seedOwner(repo memberServices.Repository, conf configs.Memory) error {
+	if conf.OwnerEmail == "" {
+		return nil
+	}
+	password, err := jwt.HashPassword(conf.OwnerPassword)
+	if err != nil {
+		return err
+	}
+	return repo.Insert(context.Background(), types.Member{
+		ID:       primitive.NewObjectID(),
+		Name:     "Owner",
+		Email:    conf.OwnerEmail,
+		Password: password,
+		Role:     types.RoleOwner,
+		Status:   types.MemberStatusActive,
+	})
 }
 
-// Close close all underlying connections
-func (c *InfraConns) Close() {
-	c.Databases.Close()
+// Close close all underlying connections, waiting for operations in progress until ctx is done
+func (c *InfraConns) Close(ctx context.Context) error {
+	err := c.Databases.Close(ctx)
+	if c.Tracing != nil {
+		if terr := c.Tracing.Shutdown(ctx); terr != nil && err == nil {
+			err = terr
+		}
+	}
+	return err
 }
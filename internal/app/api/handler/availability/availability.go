@@ -0,0 +1,102 @@
+package availabilityhandler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/respond"
+	"booking/internal/pkg/validation"
+
+)
+
+type (
+	service interface {
+		Search(ctx context.Context, req types.AvailabilityRequest) ([]types.AvailabilitySlot, error)
+	}
+
+	// Handler is availability web handler
+	Handler struct {
+		conf   *configs.Configs
+		em     *configs.ErrorMessage
+		srv    service
+		logger glog.Logger
+	}
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	validate = validation.New()
+)
+
+// New return new rest api availability handler
+func New(c *configs.Configs, e *configs.ErrorMessage, s service, l glog.Logger) *Handler {
+	return &Handler{
+		conf:   c,
+		em:     e,
+		srv:    s,
+		logger: l,
+	}
+}
+
+// Search handle availability search HTTP request,
+// query parameters: restaurant, date (YYYY-MM-DD), party_size and optional duration in minutes
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	req, invalid := h.parse(r)
+	if len(invalid) > 0 {
+		h.logger.Errorf("Failed when parse availability query, invalid: %v", invalid)
+		respond.JSON(w, http.StatusBadRequest, validation.Invalid(em, invalid...))
+		return
+	}
+
+	if err := validate.Struct(req); err != nil {
+		h.logger.Errorf("Failed when validate field availabilityRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	slots, err := h.srv.Search(r.Context(), req)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, slots)
+}
+
+// parse read the query parameters, missing ones are reported by validation
+func (h *Handler) parse(r *http.Request) (types.AvailabilityRequest, []validation.FieldError) {
+	var req types.AvailabilityRequest
+	var invalid []validation.FieldError
+	query := r.URL.Query()
+
+	req.RestaurantID = query.Get("restaurant")
+	if date := query.Get("date"); date != "" {
+		d, err := time.Parse(dateLayout, date)
+		if err != nil {
+			invalid = append(invalid, validation.FieldError{Field: "date", Rule: "datetime", Param: dateLayout})
+		}
+		req.Date = d
+	}
+	if partySize := query.Get("party_size"); partySize != "" {
+		n, err := strconv.Atoi(partySize)
+		if err != nil {
+			invalid = append(invalid, validation.FieldError{Field: "party_size", Rule: "numeric"})
+		}
+		req.PartySize = n
+	}
+	if duration := query.Get("duration"); duration != "" {
+		minutes, err := strconv.Atoi(duration)
+		if err != nil {
+			invalid = append(invalid, validation.FieldError{Field: "duration", Rule: "numeric"})
+		}
+		req.Duration = time.Duration(minutes) * time.Minute
+	}
+	return req, invalid
+}
@@ -3,15 +3,20 @@ package memberhandler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"booking/configs"
+	memberService "booking/internal/app/services/member"
 	"booking/internal/app/types"
 	"booking/internal/pkg/glog"
 	"booking/internal/pkg/respond"
+	"booking/internal/pkg/utils"
+	"booking/internal/pkg/validation"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 )
 
 type (
@@ -19,7 +24,16 @@ type (
 		Get(ctx context.Context, id string) (*types.Member, error)
 		InsertMember(ctx context.Context, MemberRequest types.MemberRequest) (*types.Member, error)
 		UpdateMemberByID(ctx context.Context, Member types.UpdateMemberRequest) error
-		Login(ctx context.Context, MemberLogin types.MemberLogin) (*types.MemberResponseSignUp, error)
+		Login(ctx context.Context, MemberLogin types.MemberLogin, ip string) (*types.MemberResponseSignUp, error)
+		Unlock(ctx context.Context, id string) error
+		LoginTwoFactor(ctx context.Context, req types.TwoFactorLoginRequest, ip string) (*types.MemberResponseSignUp, error)
+		EnrollTwoFactor(ctx context.Context) (*types.TwoFactorEnrollment, error)
+		ConfirmTwoFactor(ctx context.Context, code string) (*types.RecoveryCodes, error)
+		DisableTwoFactor(ctx context.Context, req types.TwoFactorCodeRequest) error
+		SignUp(ctx context.Context, signUp types.MemberSignUp) (*types.MemberResponse, error)
+		Verify(ctx context.Context, token string) error
+		ForgotPassword(ctx context.Context, email string) error
+		ResetPassword(ctx context.Context, req types.ResetPasswordRequest) error
 	}
 
 	// Handler is member web handler
@@ -32,7 +46,7 @@ type (
 )
 
 var (
-	validate = validator.New()
+	validate = validation.New()
 )
 
 // New return new rest api member handler
@@ -47,9 +61,10 @@ func New(c *configs.Configs, e *configs.ErrorMessage, s service, l glog.Logger)
 
 // Get handle get member HTTP request
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 	member, err := h.srv.Get(r.Context(), mux.Vars(r)["id"])
 	if err != nil {
-		respond.Error(w, err, http.StatusInternalServerError)
+		respond.Err(w, em, err)
 		return
 	}
 	respond.JSON(w, http.StatusOK, member)
@@ -57,23 +72,24 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 
 // Post hanlder insert member HTTP request
 func (h *Handler) InsertMember(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 
 	var memberRequest types.MemberRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&memberRequest); err != nil {
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	if err := validate.Struct(memberRequest); err != nil {
-		h.logger.Errorf("Failed when validate field memberRequest", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		h.logger.Errorf("Failed when validate field memberRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	mem, err := h.srv.InsertMember(r.Context(), memberRequest)
 	if err != nil {
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.Request)
+		respond.Err(w, em, err)
 		return
 	}
 
@@ -82,49 +98,255 @@ func (h *Handler) InsertMember(w http.ResponseWriter, r *http.Request) {
 
 // Put hanlder update member HTTP request
 func (h *Handler) UpdateMemberByID(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 
 	var member types.UpdateMemberRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
-		h.logger.Errorf("Failed when validate field in method UpdateMemberByID", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.Request)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	if err := validate.Struct(member); err != nil {
-		h.logger.Errorf("Failed when validate field in method UpdateMemberByID", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		h.logger.Errorf("Failed when validate field in method UpdateMemberByID, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
-	if error := h.srv.UpdateMemberByID(r.Context(), member); error != nil {
-		respond.JSON(w, http.StatusInternalServerError, h.em.InvalidValue.Request)
+	if err := h.srv.UpdateMemberByID(r.Context(), member); err != nil {
+		respond.Err(w, em, err)
 		return
 	}
 
-	respond.JSON(w, http.StatusOK, h.em.Success)
+	respond.JSON(w, http.StatusOK, em.Success)
 }
 
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 
 	var MemberLogin types.MemberLogin
 
 	if err := json.NewDecoder(r.Body).Decode(&MemberLogin); err != nil {
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	if err := validate.Struct(MemberLogin); err != nil {
-		h.logger.Errorf("Failed when validate field MemberLogin", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.Request)
+		h.logger.Errorf("Failed when validate field MemberLogin, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
-	member, err := h.srv.Login(r.Context(), MemberLogin)
+	member, err := h.srv.Login(r.Context(), MemberLogin, utils.ClientIP(r))
 	if err != nil {
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.IncorrectPasswordEmail)
+		h.throttled(w, err)
+		respond.Err(w, em, err)
 		return
 	}
 
 	respond.JSON(w, http.StatusOK, member)
 }
+
+// LoginTwoFactor handle the second step of the login of a member with 2FA HTTP request
+func (h *Handler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var login types.TwoFactorLoginRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&login); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(login); err != nil {
+		h.logger.Errorf("Failed when validate field login, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	member, err := h.srv.LoginTwoFactor(r.Context(), login, utils.ClientIP(r))
+	if err != nil {
+		h.throttled(w, err)
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, member)
+}
+
+// EnrollTwoFactor handle starting the 2FA enrollment of the authenticated member HTTP request
+func (h *Handler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	enrollment, err := h.srv.EnrollTwoFactor(r.Context())
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, enrollment)
+}
+
+// ConfirmTwoFactor handle enabling 2FA of the authenticated member HTTP request
+func (h *Handler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var confirm types.TwoFactorConfirmRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&confirm); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(confirm); err != nil {
+		h.logger.Errorf("Failed when validate field confirm, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	codes, err := h.srv.ConfirmTwoFactor(r.Context(), confirm.Code)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, codes)
+}
+
+// DisableTwoFactor handle turning 2FA of the authenticated member off HTTP request
+func (h *Handler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var disable types.TwoFactorCodeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&disable); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(disable); err != nil {
+		h.logger.Errorf("Failed when validate field disable, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	err := h.srv.DisableTwoFactor(r.Context(), disable)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
+
+// throttled set the Retry-After header of logins refused after failed ones
+func (h *Handler) throttled(w http.ResponseWriter, err error) {
+	var throttled *memberService.ThrottledError
+	if errors.As(err, &throttled) {
+		w.Header().Set("Retry-After", fmt.Sprint(int((throttled.RetryAfter+time.Second-1)/time.Second)))
+	}
+}
+
+// Unlock handle unlocking a member locked after too many failed logins HTTP request
+func (h *Handler) Unlock(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	if err := h.srv.Unlock(r.Context(), mux.Vars(r)["id"]); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
+
+// SignUp handle self-service sign up HTTP request
+func (h *Handler) SignUp(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var signUp types.MemberSignUp
+
+	if err := json.NewDecoder(r.Body).Decode(&signUp); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(signUp); err != nil {
+		h.logger.Errorf("Failed when validate field signUp, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	member, err := h.srv.SignUp(r.Context(), signUp)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, member)
+}
+
+// Verify handle email verification HTTP request
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		respond.JSON(w, http.StatusBadRequest, em.InvalidValue.InvalidToken)
+		return
+	}
+
+	if err := h.srv.Verify(r.Context(), token); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
+
+// ForgotPassword handle password reset request HTTP request,
+// it succeeds whether or not the email address is registered
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var forgotPassword types.ForgotPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&forgotPassword); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(forgotPassword); err != nil {
+		h.logger.Errorf("Failed when validate field forgotPassword, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := h.srv.ForgotPassword(r.Context(), forgotPassword.Email); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
+
+// ResetPassword handle choosing a new password with a reset token HTTP request
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var resetPassword types.ResetPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&resetPassword); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(resetPassword); err != nil {
+		h.logger.Errorf("Failed when validate field resetPassword, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := h.srv.ResetPassword(r.Context(), resetPassword); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
@@ -0,0 +1,154 @@
+package reservationhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/respond"
+	"booking/internal/pkg/validation"
+
+	"github.com/gorilla/mux"
+)
+
+type (
+	service interface {
+		Get(ctx context.Context, id string) (*types.Reservation, error)
+		Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error)
+		InsertReservation(ctx context.Context, reservationRequest types.ReservationRequest) (*types.Reservation, error)
+		UpdateReservation(ctx context.Context, reservation types.UpdateReservationRequest) error
+		DeleteReservation(ctx context.Context, id string) error
+	}
+
+	// Handler is reservation web handler
+	Handler struct {
+		conf   *configs.Configs
+		em     *configs.ErrorMessage
+		srv    service
+		logger glog.Logger
+	}
+)
+
+var (
+	validate = validation.New()
+)
+
+// New return new rest api reservation handler
+func New(c *configs.Configs, e *configs.ErrorMessage, s service, l glog.Logger) *Handler {
+	return &Handler{
+		conf:   c,
+		em:     e,
+		srv:    s,
+		logger: l,
+	}
+}
+
+// Get handle get reservation HTTP request
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	reservation, err := h.srv.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, reservation)
+}
+
+// Find handle list reservations HTTP request,
+// supported query parameters: table_id, from, to (RFC 3339)
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	query := r.URL.Query()
+	filter := types.ReservationFilter{
+		TableID: query.Get("table_id"),
+	}
+
+	var err error
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			respond.JSON(w, http.StatusBadRequest, validation.Invalid(em, validation.FieldError{Field: "from", Rule: "datetime", Param: time.RFC3339}))
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			respond.JSON(w, http.StatusBadRequest, validation.Invalid(em, validation.FieldError{Field: "to", Rule: "datetime", Param: time.RFC3339}))
+			return
+		}
+	}
+
+	reservations, err := h.srv.Find(r.Context(), filter)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, reservations)
+}
+
+// Post hanlder insert reservation HTTP request
+func (h *Handler) InsertReservation(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var reservationRequest types.ReservationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&reservationRequest); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(reservationRequest); err != nil {
+		h.logger.Errorf("Failed when validate field reservationRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	reservation, err := h.srv.InsertReservation(r.Context(), reservationRequest)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, reservation)
+}
+
+// Put hanlder update reservation HTTP request
+func (h *Handler) UpdateReservation(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var reservation types.UpdateReservationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(reservation); err != nil {
+		h.logger.Errorf("Failed when validate field in method UpdateReservation, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	err := h.srv.UpdateReservation(r.Context(), reservation)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
+
+// Delete hanlder cancel reservation HTTP request
+func (h *Handler) DeleteReservation(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	err := h.srv.DeleteReservation(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
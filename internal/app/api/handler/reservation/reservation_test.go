@@ -0,0 +1,109 @@
+package reservationhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"booking/configs"
+	reservationRepository "booking/internal/app/repositories/reservation"
+	restaurantRepository "booking/internal/app/repositories/restaurant"
+	tableRepository "booking/internal/app/repositories/table"
+	reservationService "booking/internal/app/services/reservation"
+	"booking/internal/app/types"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReservationHandler(t *testing.T) {
+	em := &configs.ErrorMessage{ConfigPath: "../../../../../configs"}
+	if err := em.Init(); err != nil {
+		t.Fatalf("Init() err = %v", err)
+	}
+
+	ctx := context.Background()
+	day := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	owner, other := primitive.NewObjectID(), primitive.NewObjectID()
+	restaurant := types.Restaurant{ID: primitive.NewObjectID(), Name: "Lotus"}
+	table := types.Table{ID: primitive.NewObjectID(), RestaurantID: restaurant.ID, Slots: 4}
+	existing := types.Reservation{ID: primitive.NewObjectID(), TableID: table.ID, MemberID: owner, PartySize: 2, StartTime: day.Add(19 * time.Hour), EndTime: day.Add(21 * time.Hour)}
+	earlier := types.Reservation{ID: primitive.NewObjectID(), TableID: table.ID, MemberID: other, PartySize: 2, StartTime: day.Add(17 * time.Hour), EndTime: day.Add(19 * time.Hour)}
+
+	restaurants := restaurantRepository.NewMemoryRepository()
+	tables := tableRepository.NewMemoryRepository()
+	reservations := reservationRepository.NewMemoryRepository()
+	if err := restaurants.Insert(ctx, restaurant); err != nil {
+		t.Fatal(err)
+	}
+	if err := tables.Insert(ctx, table); err != nil {
+		t.Fatal(err)
+	}
+	for _, reservation := range []types.Reservation{existing, earlier} {
+		if err := reservations.Insert(ctx, reservation); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := New(&configs.Configs{}, em, reservationService.NewService(&configs.Configs{}, em, reservations, tables, restaurants, glog.New()), glog.New())
+
+	r := mux.NewRouter()
+	r.Path("/reservation/{id}").Methods(http.MethodGet).HandlerFunc(h.Get)
+	r.Path("/reservation").Methods(http.MethodPost).HandlerFunc(h.InsertReservation)
+	r.Path("/reservation").Methods(http.MethodPut).HandlerFunc(h.UpdateReservation)
+	r.Path("/reservation/{id}").Methods(http.MethodDelete).HandlerFunc(h.DeleteReservation)
+
+	body := func(id string, start, end int) string {
+		b, _ := json.Marshal(map[string]interface{}{
+			"_id": id, "table_id": table.ID.Hex(), "name": "Lan", "party_size": 2,
+			"start_time": day.Add(time.Duration(start) * time.Hour), "end_time": day.Add(time.Duration(end) * time.Hour),
+		})
+		return string(b)
+	}
+	tests := []struct {
+		name   string
+		member primitive.ObjectID
+		method string
+		path   string
+		body   string
+		status int
+		code   configs.ErrorCode
+	}{
+		{"overlap", owner, http.MethodPost, "/reservation", body("", 20, 22), http.StatusConflict, em.Conflict.ReservationOverlap},
+		{"end before start", owner, http.MethodPost, "/reservation", body("", 22, 20), http.StatusBadRequest, em.InvalidValue.ValidationFailed},
+		{"update of wrong type", owner, http.MethodPut, "/reservation", `{"party_size":"two"}`, http.StatusBadRequest, em.InvalidValue.ValidationFailed},
+		{"update malformed", owner, http.MethodPut, "/reservation", `{"party_size":`, http.StatusBadRequest, em.InvalidValue.ValidationFailed},
+		{"update unknown", owner, http.MethodPut, "/reservation", body(primitive.NewObjectID().Hex(), 20, 22), http.StatusNotFound, em.Database.DataNotFound},
+		{"update overlap", owner, http.MethodPut, "/reservation", body(existing.ID.Hex(), 18, 20), http.StatusConflict, em.Conflict.ReservationOverlap},
+		{"update of other member", other, http.MethodPut, "/reservation", body(existing.ID.Hex(), 12, 14), http.StatusForbidden, em.InvalidValue.PermissionDenied},
+		{"delete of other member", other, http.MethodDelete, "/reservation/" + existing.ID.Hex(), "", http.StatusForbidden, em.InvalidValue.PermissionDenied},
+		{"get unknown", owner, http.MethodGet, "/reservation/" + primitive.NewObjectID().Hex(), "", http.StatusNotFound, em.Database.DataNotFound},
+		{"get invalid id", owner, http.MethodGet, "/reservation/bogus", "", http.StatusNotFound, em.Database.DataNotFound},
+		{"update by owner", owner, http.MethodPut, "/reservation", body(existing.ID.Hex(), 12, 14), http.StatusOK, em.Success},
+		{"delete by owner", owner, http.MethodDelete, "/reservation/" + existing.ID.Hex(), "", http.StatusOK, em.Success},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req = req.WithContext(auth.NewContext(req.Context(), &types.Claims{ID: tt.member, Role: types.RoleGuest}))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d; expected %d, body: %s", w.Code, tt.status, w.Body)
+			}
+			var code configs.ErrorCode
+			if err := json.Unmarshal(w.Body.Bytes(), &code); err != nil {
+				t.Fatalf("Unmarshal() err = %v", err)
+			}
+			if code != tt.code {
+				t.Errorf("code = %+v; expected %+v", code, tt.code)
+			}
+		})
+	}
+}
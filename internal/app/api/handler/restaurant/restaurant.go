@@ -0,0 +1,157 @@
+package restauranthandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/respond"
+	"booking/internal/pkg/validation"
+
+	"github.com/gorilla/mux"
+)
+
+type (
+	service interface {
+		Get(ctx context.Context, id string) (*types.Restaurant, error)
+		FindAll(ctx context.Context) ([]types.Restaurant, error)
+		FindTables(ctx context.Context, id string) ([]types.Table, error)
+		InsertRestaurant(ctx context.Context, restaurantRequest types.RestaurantRequest) (*types.Restaurant, error)
+		UpdateRestaurantByID(ctx context.Context, restaurant types.UpdateRestaurantRequest) error
+		DeleteRestaurant(ctx context.Context, restaurant types.DeleteRestaurantRequest) error
+	}
+
+	// Handler is restaurant web handler
+	Handler struct {
+		conf   *configs.Configs
+		em     *configs.ErrorMessage
+		srv    service
+		logger glog.Logger
+	}
+)
+
+var (
+	validate = validation.New()
+)
+
+// New return new rest api restaurant handler
+func New(c *configs.Configs, e *configs.ErrorMessage, s service, l glog.Logger) *Handler {
+	return &Handler{
+		conf:   c,
+		em:     e,
+		srv:    s,
+		logger: l,
+	}
+}
+
+// Get handle get restaurant HTTP request
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	restaurant, err := h.srv.Get(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, restaurant)
+}
+
+// FindAll handle list restaurants HTTP request
+func (h *Handler) FindAll(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	restaurants, err := h.srv.FindAll(r.Context())
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, restaurants)
+}
+
+// FindTables handle list tables of a restaurant HTTP request
+func (h *Handler) FindTables(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	tables, err := h.srv.FindTables(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, tables)
+}
+
+// Post hanlder insert restaurant HTTP request
+func (h *Handler) InsertRestaurant(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var restaurantRequest types.RestaurantRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&restaurantRequest); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(restaurantRequest); err != nil {
+		h.logger.Errorf("Failed when validate field restaurantRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	restaurant, err := h.srv.InsertRestaurant(r.Context(), restaurantRequest)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, restaurant)
+}
+
+// Put hanlder update restaurant HTTP request
+func (h *Handler) UpdateRestaurantByID(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var restaurant types.UpdateRestaurantRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&restaurant); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(restaurant); err != nil {
+		h.logger.Errorf("Failed when validate field in method UpdateRestaurantByID, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := h.srv.UpdateRestaurantByID(r.Context(), restaurant); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
+
+// Put hanlder delete restaurant HTTP request
+func (h *Handler) DeleteRestaurant(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var restaurant types.DeleteRestaurantRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&restaurant); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(restaurant); err != nil {
+		h.logger.Errorf("Failed when validate field in method DeleteRestaurant, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := h.srv.DeleteRestaurant(r.Context(), restaurant); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
@@ -0,0 +1,88 @@
+package sessionhandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/respond"
+	"booking/internal/pkg/validation"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	service interface {
+		Refresh(ctx context.Context, refreshToken string) (*types.Tokens, error)
+		Revoke(ctx context.Context, id primitive.ObjectID) error
+	}
+
+	// Handler is session web handler
+	Handler struct {
+		conf   *configs.Configs
+		em     *configs.ErrorMessage
+		srv    service
+		logger glog.Logger
+	}
+)
+
+var (
+	validate = validation.New()
+)
+
+// New return new rest api session handler
+func New(c *configs.Configs, e *configs.ErrorMessage, s service, l glog.Logger) *Handler {
+	return &Handler{
+		conf:   c,
+		em:     e,
+		srv:    s,
+		logger: l,
+	}
+}
+
+// Refresh handle exchanging a refresh token for new tokens HTTP request
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+
+	var refreshRequest types.RefreshRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&refreshRequest); err != nil {
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	if err := validate.Struct(refreshRequest); err != nil {
+		h.logger.Errorf("Failed when validate field refreshRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
+		return
+	}
+
+	tokens, err := h.srv.Refresh(r.Context(), refreshRequest.RefreshToken)
+	if err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, tokens)
+}
+
+// Logout handle revoking the session of the authenticated member HTTP request
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
+	claims, ok := auth.FromContext(r.Context())
+	if !ok {
+		respond.JSON(w, http.StatusUnauthorized, em.InvalidValue.FailedAuthentication)
+		return
+	}
+
+	if err := h.srv.Revoke(r.Context(), claims.SessionID); err != nil {
+		respond.Err(w, em, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, em.Success)
+}
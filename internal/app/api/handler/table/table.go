@@ -9,8 +9,8 @@ import (
 	"booking/internal/app/types"
 	"booking/internal/pkg/glog"
 	"booking/internal/pkg/respond"
+	"booking/internal/pkg/validation"
 
-	"github.com/go-playground/validator/v10"
 	//"github.com/gorilla/mux"
 )
 
@@ -32,7 +32,7 @@ type (
 )
 
 var (
-	validate = validator.New()
+	validate = validation.New()
 )
 
 // New return new rest api table handler
@@ -47,23 +47,24 @@ func New(c *configs.Configs, e *configs.ErrorMessage, s service, l glog.Logger)
 
 // Post hanlder insert table HTTP request
 func (h *Handler) InsertTable(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 
 	var tableRequest types.TableRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&tableRequest); err != nil {
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	if err := validate.Struct(tableRequest); err != nil {
-		h.logger.Errorf("Failed when validate field tableRequest", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		h.logger.Errorf("Failed when validate field tableRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	tableReq, err := h.srv.InsertTable(r.Context(), tableRequest)
 	if err != nil {
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.Request)
+		respond.Err(w, em, err)
 		return
 	}
 
@@ -72,52 +73,52 @@ func (h *Handler) InsertTable(w http.ResponseWriter, r *http.Request) {
 
 // Put hanlder update table HTTP request
 func (h *Handler) UpdateTableByID(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 
 	var table types.UpdateTableRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
-		h.logger.Errorf("Failed when validate field in method UpdateTableRequest", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.Request)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	if err := validate.Struct(table); err != nil {
-		h.logger.Errorf("Failed when validate field in method UpdateTableRequest", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		h.logger.Errorf("Failed when validate field in method UpdateTableRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
-	if error := h.srv.UpdateTableByID(r.Context(), table); error != nil {
-		respond.JSON(w, http.StatusInternalServerError, h.em.InvalidValue.Request)
+	if err := h.srv.UpdateTableByID(r.Context(), table); err != nil {
+		respond.Err(w, em, err)
 		return
 	}
 
-	respond.JSON(w, http.StatusOK, h.em.Success)
+	respond.JSON(w, http.StatusOK, em.Success)
 }
 
 // Put hanlder delete table HTTP request
 func (h *Handler) DeleteTable(w http.ResponseWriter, r *http.Request) {
+	em := respond.Lang(r, h.em)
 
 	var table types.DeleteTableRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
-		h.logger.Errorf("Failed when validate field in method DeleteTableRequest", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.Request)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
 	if err := validate.Struct(table); err != nil {
-		h.logger.Errorf("Failed when validate field in method DeleteTableRequest", err)
-		respond.JSON(w, http.StatusBadRequest, h.em.InvalidValue.ValidationFailed)
+		h.logger.Errorf("Failed when validate field in method DeleteTableRequest, err: %v", err)
+		respond.JSON(w, http.StatusBadRequest, validation.Response(em, err))
 		return
 	}
 
-	if error := h.srv.DeleteTable(r.Context(), table); error != nil {
-		respond.JSON(w, http.StatusInternalServerError, h.em.InvalidValue.Request)
+	if err := h.srv.DeleteTable(r.Context(), table); err != nil {
+		respond.Err(w, em, err)
 		return
 	}
 
-	respond.JSON(w, http.StatusOK, h.em.Success)
+	respond.JSON(w, http.StatusOK, em.Success)
 }
 
 
@@ -1,32 +1,92 @@
 package db
 
-import "github.com/globalsign/mgo"
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+
+	"booking/internal/pkg/apperr"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
 
 const (
 	TypeMongoDB = "mongodb"
 	TypeMySQL   = "mysql"
+	// TypeMemory keep data in memory, for local runs and tests without database
+	TypeMemory = "memory"
 )
 
 type (
-	// Connections all supported types of database connections
+	// Connections all supported types of database connections,
+	// only the one of the configured type is open
 	Connections struct {
 		Type    string
-		MongoDB *mgo.Session
+		MongoDB *Mongo
+		SQL     *SQL
 	}
 )
 
+var (
+	// ErrOverlap is returned when a reservation overlaps another reservation of the same table
+	ErrOverlap = apperr.New(apperr.Conflict, "conflict.reservation_overlap", "reservation overlaps an existing reservation")
+	// ErrNotFound is returned when no document or row matches, or the id is invalid
+	ErrNotFound = apperr.New(apperr.NotFound, "database.data_not_found", "not found")
+	// ErrDuplicateKey is returned when a document with the same id or unique field exists
+	ErrDuplicateKey = apperr.New(apperr.Conflict, "conflict.data_exists", "duplicate key")
+)
+
 // IsErrNotFound return true if the given error is a not found error
 func IsErrNotFound(err error) bool {
-	return err == mgo.ErrNotFound
+	return errors.Is(err, ErrNotFound) || errors.Is(err, mongo.ErrNoDocuments)
+}
+
+// MongoError return ErrNotFound when no document matches or the id is invalid and ErrDuplicateKey
+// for duplicate key errors so every repository reports them the same way, other errors are
+// unavailable database errors. Domain errors are returned as is
+func MongoError(err error) error {
+	if _, ok := apperr.As(err); ok || err == nil {
+		return err
+	}
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments), invalidID(err):
+		return ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return ErrDuplicateKey
+	}
+	return unavailable(err)
 }
 
-// Close close all underlying connections
-func (c *Connections) Close() error {
-	switch c.Type {
-	case TypeMongoDB:
-		if c.MongoDB != nil {
-			c.MongoDB.Close()
-		}
+// invalidID return true if err is the error of parsing an invalid object id
+func invalidID(err error) bool {
+	var hexErr hex.InvalidByteError
+	return errors.Is(err, primitive.ErrInvalidHex) || errors.As(err, &hexErr)
+}
+
+// unavailable return the error of a failed database operation
+func unavailable(err error) error {
+	return apperr.Wrap(apperr.Unavailable, "database.database", err)
+}
+
+// Ping check the open database can be reached, it is a health check
+func (c *Connections) Ping(ctx context.Context) error {
+	switch {
+	case c.MongoDB != nil:
+		return c.MongoDB.Ping(ctx)
+	case c.SQL != nil:
+		return c.SQL.PingContext(ctx)
+	}
+	return nil
+}
+
+// Close close all underlying connections, waiting for operations in progress until ctx is done
+func (c *Connections) Close(ctx context.Context) error {
+	if c.MongoDB != nil {
+		return c.MongoDB.Disconnect(ctx)
+	}
+	if c.SQL != nil {
+		return c.SQL.Close()
 	}
 	return nil
 }
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"booking/internal/pkg/apperr"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMongoError(t *testing.T) {
+	_, invalidHex := primitive.ObjectIDFromHex("not-an-id")
+	tests := []struct {
+		name string
+		err  error
+		kind apperr.Kind
+	}{
+		{"no documents", mongo.ErrNoDocuments, apperr.NotFound},
+		{"invalid id", invalidHex, apperr.NotFound},
+		{"duplicate key", mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, apperr.Conflict},
+		{"domain error", ErrOverlap, apperr.Conflict},
+		{"server down", errors.New("server selection timeout"), apperr.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MongoError(tt.err)
+			if kind := apperr.KindOf(err); kind != tt.kind {
+				t.Errorf("kind of %v = %v; expected %v", err, kind, tt.kind)
+			}
+			if !errors.Is(err, tt.err) && tt.kind == apperr.Unavailable {
+				t.Errorf("MongoError(%v) lost the cause", tt.err)
+			}
+		})
+	}
+	if err := MongoError(nil); err != nil {
+		t.Errorf("MongoError(nil) = %v; expected nil", err)
+	}
+	if err := MongoError(fmt.Errorf("find: %w", ErrNotFound)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("MongoError() of a wrapped domain error = %v; expected %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"booking/configs"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	// DefaultMongoDatabase is the database used when none is configured
+	DefaultMongoDatabase = "booking"
+	// DefaultMongoTimeout bound connecting and selecting a server when no timeout is configured
+	DefaultMongoTimeout = 10 * time.Second
+)
+
+// Mongo is the MongoDB database of a deployment, the collections of several deployments
+// can share a database with distinct prefixes or be renamed one by one
+type Mongo struct {
+	database *mongo.Database
+	prefix   string
+	names    map[string]string
+	timeout  time.Duration
+}
+
+// ConnectMongo connect to the MongoDB server configured by conf with its pool, timeout and
+// credentials and ping it, the connection must be closed with Disconnect
+func ConnectMongo(ctx context.Context, conf configs.MongoDB, logger glog.Logger) (*Mongo, error) {
+	timeout := conf.Timeout
+	if timeout <= 0 {
+		timeout = DefaultMongoTimeout
+	}
+
+	opts := options.Client().
+		ApplyURI(mongoURI(conf.Address)).
+		SetConnectTimeout(timeout).
+		SetServerSelectionTimeout(timeout).
+		SetMonitor(commandMonitor())
+	if conf.Username != "" {
+		opts.SetAuth(options.Credential{
+			AuthSource: conf.AuthSource,
+			Username:   conf.Username,
+			Password:   conf.Password,
+		})
+	}
+	if conf.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(conf.MaxPoolSize)
+	}
+	if conf.MinPoolSize > 0 {
+		opts.SetMinPoolSize(conf.MinPoolSize)
+	}
+	if conf.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(conf.MaxConnIdleTime)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMongo(client, conf)
+	m.timeout = timeout
+	if err := m.Ping(ctx); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping MongoDB, err: %v", err)
+	}
+	logger.Infof("connected to MongoDB database %v", m.Name())
+	return m, nil
+}
+
+// NewMongo return the database configured by conf of a connected client
+func NewMongo(client *mongo.Client, conf configs.MongoDB) *Mongo {
+	name := conf.Database
+	if name == "" {
+		name = DefaultMongoDatabase
+	}
+	return &Mongo{
+		database: client.Database(name),
+		prefix:   conf.CollectionPrefix,
+		names:    conf.Collections,
+		timeout:  DefaultMongoTimeout,
+	}
+}
+
+// mongoURI return the connection string of address, addresses without
+// mongodb:// or mongodb+srv:// scheme are SRV records
+func mongoURI(address string) string {
+	if strings.HasPrefix(address, "mongodb://") || strings.HasPrefix(address, "mongodb+srv://") {
+		return address
+	}
+	return fmt.Sprintf("mongodb+srv://%s", address)
+}
+
+// Collection return the collection with given name, renamed by the
+// collections setting and prefixed by the collection prefix
+func (m *Mongo) Collection(name string) *mongo.Collection {
+	if renamed, ok := m.names[name]; ok && renamed != "" {
+		name = renamed
+	}
+	return m.database.Collection(m.prefix + name)
+}
+
+// Client return the client of the database, to start sessions
+func (m *Mongo) Client() *mongo.Client {
+	return m.database.Client()
+}
+
+// Name return the name of the database
+func (m *Mongo) Name() string {
+	return m.database.Name()
+}
+
+// Ping check the primary server can be reached within the timeout, it is a health check
+func (m *Mongo) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	return m.Client().Ping(ctx, readpref.Primary())
+}
+
+// Disconnect wait for the operations in progress until ctx is done and close the connections
+func (m *Mongo) Disconnect(ctx context.Context) error {
+	return m.Client().Disconnect(ctx)
+}
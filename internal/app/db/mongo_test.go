@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"booking/configs"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestMongoCollection(t *testing.T) {
+	// connecting does not reach the server until an operation runs
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+
+	tests := []struct {
+		conf       configs.MongoDB
+		database   string
+		collection string
+	}{
+		{configs.MongoDB{}, "booking", "members"},
+		{configs.MongoDB{Database: "staging"}, "staging", "members"},
+		{configs.MongoDB{Database: "tenants", CollectionPrefix: "acme_"}, "tenants", "acme_members"},
+		{configs.MongoDB{CollectionPrefix: "acme_", Collections: map[string]string{"members": "users"}}, "booking", "acme_users"},
+	}
+	for _, tt := range tests {
+		c := NewMongo(client, tt.conf).Collection("members")
+		if c.Database().Name() != tt.database || c.Name() != tt.collection {
+			t.Errorf("collection of %+v = %s.%s; expected %s.%s", tt.conf, c.Database().Name(), c.Name(), tt.database, tt.collection)
+		}
+	}
+}
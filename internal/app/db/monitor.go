@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/trace"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// command is a MongoDB command in progress
+type command struct {
+	collection string
+	span       *trace.Span
+}
+
+// commandMonitor observe the latency of MongoDB commands by command and collection
+// and trace the commands of traced contexts
+func commandMonitor() *event.CommandMonitor {
+	// commands in progress by request id
+	var commands sync.Map
+
+	finished := func(e event.CommandFinishedEvent, result string, err error) {
+		value, _ := commands.LoadAndDelete(e.RequestID)
+		cmd, _ := value.(command)
+		metrics.DatabaseOperationDuration.Observe(time.Duration(e.DurationNanos).Seconds(), e.CommandName, cmd.collection, result)
+		if cmd.span != nil {
+			cmd.span.SetError(err)
+			cmd.span.End()
+		}
+	}
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			// commands on a collection, e.g. find or insert, name it as their first value
+			name, _ := e.Command.Lookup(e.CommandName).StringValueOK()
+			cmd := command{collection: name}
+			if span, ok := startSpan(ctx, "mongodb", e.CommandName); ok {
+				span.SetAttribute("db.name", e.DatabaseName)
+				span.SetAttribute("db.mongodb.collection", name)
+				cmd.span = span
+			}
+			commands.Store(e.RequestID, cmd)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			finished(e.CommandFinishedEvent, "success", nil)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			finished(e.CommandFinishedEvent, "failure", errors.New(e.Failure))
+		},
+	}
+}
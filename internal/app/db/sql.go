@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"booking/internal/pkg/apperr"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+const (
+	TypePostgres = "postgres"
+	// TypeSQLite need cgo, binaries built with CGO_ENABLED=0 fail to open it
+	TypeSQLite = "sqlite"
+)
+
+// drivers map the database types to the name of their database/sql driver
+var drivers = map[string]string{
+	TypeMySQL:    "mysql",
+	TypePostgres: "postgres",
+	TypeSQLite:   "sqlite3",
+}
+
+// columnTypes replace the column types used in schemas by the ones of each dialect
+var columnTypes = map[string]*strings.Replacer{
+	TypeMySQL:    strings.NewReplacer("{time}", "DATETIME(3)", "{text}", "TEXT"),
+	TypePostgres: strings.NewReplacer("{time}", "TIMESTAMPTZ", "{text}", "TEXT"),
+	TypeSQLite:   strings.NewReplacer("{time}", "DATETIME", "{text}", "TEXT"),
+}
+
+// SQL is a database/sql connection pool with the dialect of its database
+type SQL struct {
+	*sql.DB
+	Type string
+}
+
+// IsSQL return true if the database type is handled by database/sql
+func IsSQL(dbType string) bool {
+	_, ok := drivers[dbType]
+	return ok
+}
+
+// OpenSQL open a connection pool to the database of given type, MySQL DSNs need parseTime=true
+func OpenSQL(dbType, dsn string) (*SQL, error) {
+	driver, ok := drivers[dbType]
+	if !ok {
+		return nil, fmt.Errorf("database type not supported: %s", dbType)
+	}
+	conn, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if dbType == TypeSQLite {
+		// SQLite allows a single writer, in-memory databases only live in their connection
+		conn.SetMaxOpenConns(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &SQL{DB: conn, Type: dbType}, nil
+}
+
+// Rebind replace the ? placeholders of query by the ones of the dialect
+func (s *SQL) Rebind(query string) string {
+	if s.Type != TypePostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Schema replace the {time} and {text} column types of a CREATE statement by the ones of the dialect
+func (s *SQL) Schema(ddl string) string {
+	return columnTypes[s.Type].Replace(ddl)
+}
+
+// InsertIgnore turn an INSERT statement into one doing nothing when the row exists
+func (s *SQL) InsertIgnore(query string) string {
+	switch s.Type {
+	case TypeMySQL:
+		return strings.Replace(query, "INSERT INTO", "INSERT IGNORE INTO", 1)
+	case TypeSQLite:
+		return strings.Replace(query, "INSERT INTO", "INSERT OR IGNORE INTO", 1)
+	default:
+		return query + " ON CONFLICT DO NOTHING"
+	}
+}
+
+// Error return ErrNotFound when no row matches and ErrDuplicateKey when a unique
+// constraint fails so every repository reports them the same way, other errors are
+// unavailable database errors. Domain errors are returned as is
+func (s *SQL) Error(err error) error {
+	if _, ok := apperr.As(err); ok || err == nil {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) || invalidID(err) {
+		return ErrNotFound
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return ErrDuplicateKey
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateKey
+	}
+	if isSQLiteDuplicate(err) {
+		return ErrDuplicateKey
+	}
+	return unavailable(err)
+}
+
+// CreateIndex create the index of a table if it does not exist yet
+func (s *SQL) CreateIndex(ctx context.Context, name, table, columns string) error {
+	if s.Type != TypeMySQL {
+		_, err := s.ExecContext(ctx, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", name, table, columns))
+		return err
+	}
+
+	// MySQL has no CREATE INDEX IF NOT EXISTS
+	var n int
+	err := s.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?", table, name).Scan(&n)
+	if err != nil || n > 0 {
+		return err
+	}
+	_, err = s.ExecContext(ctx, fmt.Sprintf("CREATE INDEX %s ON %s (%s)", name, table, columns))
+	return err
+}
+
+// Scanner is a row or rows of a query
+type Scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Execer is a connection pool or a transaction
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Time scan a nullable time column, NULL is scanned as the zero time
+type Time time.Time
+
+// Scan implements sql.Scanner
+func (t *Time) Scan(value interface{}) error {
+	var n sql.NullTime
+	if err := n.Scan(value); err != nil {
+		return err
+	}
+	*t = Time(n.Time)
+	return nil
+}
+
+// NullTime return a NULL for the zero time
+func NullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
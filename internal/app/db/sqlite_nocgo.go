@@ -0,0 +1,13 @@
+//go:build !cgo
+// +build !cgo
+
+package db
+
+import (
+	// registers a driver failing to open databases, SQLite needs cgo
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func isSQLiteDuplicate(err error) bool {
+	return false
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"booking/internal/pkg/trace"
+)
+
+// startSpan start a client span of a database operation when ctx is traced, operations
+// outside requests such as creating tables on start are not
+func startSpan(ctx context.Context, system, operation string) (*trace.Span, bool) {
+	if _, ok := trace.FromContext(ctx); !ok {
+		return nil, false
+	}
+	_, span := trace.StartKind(ctx, system+"."+operation, trace.KindClient)
+	span.SetAttribute("db.system", system)
+	span.SetAttribute("db.operation", operation)
+	return span, true
+}
+
+// startQuerySpan start the span of a SQL statement, named by its first keyword
+func (s *SQL) startQuerySpan(ctx context.Context, query string) (*trace.Span, bool) {
+	operation := strings.TrimSpace(query)
+	if i := strings.IndexAny(operation, " \t\n"); i > 0 {
+		operation = operation[:i]
+	}
+	span, ok := startSpan(ctx, s.Type, strings.ToUpper(operation))
+	if ok {
+		span.SetAttribute("db.statement", query)
+	}
+	return span, ok
+}
+
+// ExecContext execute a statement, see sql.DB.ExecContext
+func (s *SQL) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	span, ok := s.startQuerySpan(ctx, query)
+	res, err := s.DB.ExecContext(ctx, query, args...)
+	if ok {
+		span.SetError(err)
+		span.End()
+	}
+	return res, err
+}
+
+// QueryContext execute a query, see sql.DB.QueryContext. The span ends before rows are read
+func (s *SQL) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	span, ok := s.startQuerySpan(ctx, query)
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if ok {
+		span.SetError(err)
+		span.End()
+	}
+	return rows, err
+}
+
+// QueryRowContext execute a query returning a row, see sql.DB.QueryRowContext
+func (s *SQL) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	span, ok := s.startQuerySpan(ctx, query)
+	row := s.DB.QueryRowContext(ctx, query, args...)
+	if ok {
+		if err := row.Err(); err != nil && err != sql.ErrNoRows {
+			span.SetError(err)
+		}
+		span.End()
+	}
+	return row
+}
@@ -0,0 +1,159 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All is every migration of the booking database, new migrations are appended with the next version
+var All = []Migration{
+	{
+		Version:     1,
+		Description: "unique index on members email",
+		Up: func(ctx context.Context, database *db.Mongo) error {
+			if err := checkDuplicateEmails(ctx, database); err != nil {
+				return err
+			}
+			return createIndexes("members", mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetName("email_1").SetUnique(true),
+			})(ctx, database)
+		},
+		Down: dropIndexes("members", "email_1"),
+	},
+	{
+		Version:     2,
+		Description: "index tables by restaurant",
+		Up: createIndexes("tables", mongo.IndexModel{
+			Keys:    bson.D{{Key: "restaurant_id", Value: 1}, {Key: "del_flg", Value: 1}},
+			Options: options.Index().SetName("restaurant_id_1_del_flg_1"),
+		}),
+		Down: dropIndexes("tables", "restaurant_id_1_del_flg_1"),
+	},
+	{
+		Version:     3,
+		Description: "index reservations by table and by member with start time",
+		Up: createIndexes("reservations", mongo.IndexModel{
+			Keys:    bson.D{{Key: "table_id", Value: 1}, {Key: "start_time", Value: 1}},
+			Options: options.Index().SetName("table_id_1_start_time_1"),
+		}, mongo.IndexModel{
+			Keys:    bson.D{{Key: "member_id", Value: 1}, {Key: "start_time", Value: 1}},
+			Options: options.Index().SetName("member_id_1_start_time_1"),
+		}),
+		Down: dropIndexes("reservations", "table_id_1_start_time_1", "member_id_1_start_time_1"),
+	},
+	{
+		Version:     4,
+		Description: "index sessions by member and member tokens by hash, expire member tokens",
+		Up: func(ctx context.Context, database *db.Mongo) error {
+			err := createIndexes("sessions", mongo.IndexModel{
+				Keys:    bson.D{{Key: "member_id", Value: 1}},
+				Options: options.Index().SetName("member_id_1"),
+			})(ctx, database)
+			if err != nil {
+				return err
+			}
+			return createIndexes("member_tokens", mongo.IndexModel{
+				Keys:    bson.D{{Key: "hash", Value: 1}},
+				Options: options.Index().SetName("hash_1"),
+			}, mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetName("expires_at_1").SetExpireAfterSeconds(0),
+			})(ctx, database)
+		},
+		Down: func(ctx context.Context, database *db.Mongo) error {
+			if err := dropIndexes("sessions", "member_id_1")(ctx, database); err != nil {
+				return err
+			}
+			return dropIndexes("member_tokens", "hash_1", "expires_at_1")(ctx, database)
+		},
+	},
+	{
+		// members signed up before email verification have no status and
+		// documents inserted by hand may miss the delete flag queries filter on
+		Version:     5,
+		Description: "backfill members status and delete flags",
+		Up: func(ctx context.Context, database *db.Mongo) error {
+			missing := bson.M{"$exists": false}
+			_, err := database.Collection("members").UpdateMany(ctx,
+				bson.M{"$or": bson.A{bson.M{"status": missing}, bson.M{"status": ""}}},
+				bson.M{"$set": bson.M{"status": types.MemberStatusActive}})
+			if err != nil {
+				return err
+			}
+			for _, name := range []string{"restaurants", "tables", "reservations"} {
+				_, err := database.Collection(name).UpdateMany(ctx, bson.M{"del_flg": missing}, bson.M{"$set": bson.M{"del_flg": false}})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// duplicateEmail is an email shared by several members
+type duplicateEmail struct {
+	Email string               `bson:"_id"`
+	IDs   []primitive.ObjectID `bson:"ids"`
+}
+
+// checkDuplicateEmails return an error naming the members sharing an email,
+// the unique index can not be built until they are merged or removed
+func checkDuplicateEmails(ctx context.Context, database *db.Mongo) error {
+	cursor, err := database.Collection("members").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$email", "ids": bson.M{"$push": "$_id"}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	var duplicates []duplicateEmail
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return err
+	}
+	return duplicateEmailsError(duplicates)
+}
+
+func duplicateEmailsError(duplicates []duplicateEmail) error {
+	if len(duplicates) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(duplicates))
+	for _, d := range duplicates {
+		ids := make([]string, 0, len(d.IDs))
+		for _, id := range d.IDs {
+			ids = append(ids, id.Hex())
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", d.Email, strings.Join(ids, ", ")))
+	}
+	return fmt.Errorf("members share an email, merge or remove them and migrate again:\n%s", strings.Join(lines, "\n"))
+}
+
+func createIndexes(collection string, models ...mongo.IndexModel) func(ctx context.Context, database *db.Mongo) error {
+	return func(ctx context.Context, database *db.Mongo) error {
+		_, err := database.Collection(collection).Indexes().CreateMany(ctx, models)
+		return err
+	}
+}
+
+func dropIndexes(collection string, names ...string) func(ctx context.Context, database *db.Mongo) error {
+	return func(ctx context.Context, database *db.Mongo) error {
+		for _, name := range names {
+			if _, err := database.Collection(collection).Indexes().DropOne(ctx, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Usage describe the arguments of the migrate command
+const Usage = `usage: booking migrate <command>
+  up [version]  apply pending migrations, up to version when given
+  down [steps]  revert the latest applied migrations, 1 by default
+  status        list migrations and when they were applied`
+
+// Run the migrate command with given arguments, output is written to w
+func Run(ctx context.Context, m *Migrator, args []string, w io.Writer) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(Usage)
+	}
+
+	switch args[0] {
+	case "up":
+		version, err := intArg(args, 0)
+		if err != nil {
+			return err
+		}
+		n, err := m.Up(ctx, version)
+		fmt.Fprintf(w, "%d migration(s) applied\n", n)
+		return err
+
+	case "down":
+		steps, err := intArg(args, 1)
+		if err != nil {
+			return err
+		}
+		n, err := m.Down(ctx, steps)
+		fmt.Fprintf(w, "%d migration(s) reverted\n", n)
+		return err
+
+	case "status":
+		if len(args) > 1 {
+			return errors.New(Usage)
+		}
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tAPPLIED AT\tDESCRIPTION")
+		for _, s := range status {
+			appliedAt := "pending"
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, appliedAt, s.Description)
+		}
+		return tw.Flush()
+	}
+	return errors.New(Usage)
+}
+
+// intArg return the optional positive number following the command or def when there is none
+func intArg(args []string, def int) (int, error) {
+	if len(args) < 2 {
+		return def, nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number %q\n%s", args[1], Usage)
+	}
+	return n, nil
+}
@@ -0,0 +1,161 @@
+// Package migrations hold the versioned changes of the MongoDB database, indexes and
+// data backfills, applied in order and recorded in the schema_migrations collection
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/pkg/glog"
+)
+
+type (
+	// Migration is a versioned change of the database, Down undoes Up
+	// and is nil when there is nothing to undo, e.g. for backfills.
+	// Up must be safe to run twice as instances starting together may both run it.
+	Migration struct {
+		Version     int
+		Description string
+		Up          func(ctx context.Context, database *db.Mongo) error
+		Down        func(ctx context.Context, database *db.Mongo) error
+	}
+
+	// Status tell if a migration is applied, AppliedAt is zero for pending migrations
+	Status struct {
+		Version     int
+		Description string
+		AppliedAt   time.Time
+	}
+
+	// Store record the applied migrations, Record returns db.ErrDuplicateKey
+	// when the migration is already recorded
+	Store interface {
+		Applied(ctx context.Context) (map[int]time.Time, error)
+		Record(ctx context.Context, migration Migration, at time.Time) error
+		Remove(ctx context.Context, version int) error
+	}
+
+	// Migrator apply and revert migrations of a database
+	Migrator struct {
+		database   *db.Mongo
+		store      Store
+		migrations []Migration
+		logger     glog.Logger
+	}
+)
+
+// New return a migrator of all migrations of the database
+func New(database *db.Mongo, logger glog.Logger) *Migrator {
+	return NewMigrator(database, NewMongoStore(database), All, logger)
+}
+
+// NewMigrator return a migrator of given migrations, it panics when two migrations share a version
+func NewMigrator(database *db.Mongo, store Store, migrations []Migration, logger glog.Logger) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			panic(fmt.Sprintf("duplicate migration version %d", sorted[i].Version))
+		}
+	}
+	return &Migrator{
+		database:   database,
+		store:      store,
+		migrations: sorted,
+		logger:     logger,
+	}
+}
+
+// Status return every migration in order of version with the time it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status = append(status, Status{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   applied[migration.Version],
+		})
+	}
+	return status, nil
+}
+
+// Pending return the migrations not applied yet in order of version
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.store.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up apply the pending migrations up to version in order, all of them when version is 0.
+// It stops at the first failing migration and return the number of applied migrations.
+func (m *Migrator) Up(ctx context.Context, version int) (int, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, migration := range pending {
+		if version > 0 && migration.Version > version {
+			break
+		}
+		m.logger.Infof("applying migration %d: %s", migration.Version, migration.Description)
+		if err := migration.Up(ctx, m.database); err != nil {
+			return n, fmt.Errorf("migration %d failed, err: %v", migration.Version, err)
+		}
+		if err := m.store.Record(ctx, migration, time.Now()); err != nil {
+			if errors.Is(err, db.ErrDuplicateKey) {
+				m.logger.Infof("migration %d was applied by another run", migration.Version)
+				continue
+			}
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Down revert the given number of applied migrations, latest first,
+// and return the number of reverted migrations
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	applied, err := m.store.Applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := len(m.migrations) - 1; i >= 0 && n < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		m.logger.Infof("reverting migration %d: %s", migration.Version, migration.Description)
+		if migration.Down != nil {
+			if err := migration.Down(ctx, m.database); err != nil {
+				return n, fmt.Errorf("migration %d failed, err: %v", migration.Version, err)
+			}
+		}
+		if err := m.store.Remove(ctx, migration.Version); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
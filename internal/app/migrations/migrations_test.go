@@ -0,0 +1,180 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryStore struct {
+	applied map[int]time.Time
+}
+
+func (s *memoryStore) Applied(ctx context.Context) (map[int]time.Time, error) {
+	applied := map[int]time.Time{}
+	for version, at := range s.applied {
+		applied[version] = at
+	}
+	return applied, nil
+}
+
+func (s *memoryStore) Record(ctx context.Context, migration Migration, at time.Time) error {
+	if _, ok := s.applied[migration.Version]; ok {
+		return db.ErrDuplicateKey
+	}
+	s.applied[migration.Version] = at
+	return nil
+}
+
+func (s *memoryStore) Remove(ctx context.Context, version int) error {
+	delete(s.applied, version)
+	return nil
+}
+
+// newTestMigrator return a migrator of migrations 1 to 3 logging the steps run,
+// migration 3 has no Down and failing versions return an error
+func newTestMigrator(log *[]string, failing ...int) (*Migrator, *memoryStore) {
+	step := func(name string, version int) func(ctx context.Context, database *db.Mongo) error {
+		return func(ctx context.Context, database *db.Mongo) error {
+			for _, v := range failing {
+				if v == version {
+					return errors.New("boom")
+				}
+			}
+			*log = append(*log, name)
+			return nil
+		}
+	}
+	store := &memoryStore{applied: map[int]time.Time{}}
+	migrations := []Migration{
+		{Version: 3, Description: "three", Up: step("up3", 3)},
+		{Version: 1, Description: "one", Up: step("up1", 1), Down: step("down1", 1)},
+		{Version: 2, Description: "two", Up: step("up2", 2), Down: step("down2", 2)},
+	}
+	return NewMigrator(nil, store, migrations, glog.New()), store
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("up and down in order", func(t *testing.T) {
+		var log []string
+		m, store := newTestMigrator(&log)
+
+		if n, err := m.Up(ctx, 2); err != nil || n != 2 {
+			t.Fatalf("Up(2) = %d, %v; expected 2 applied", n, err)
+		}
+		if n, err := m.Up(ctx, 0); err != nil || n != 1 {
+			t.Fatalf("Up(0) = %d, %v; expected 1 applied", n, err)
+		}
+		if n, err := m.Up(ctx, 0); err != nil || n != 0 {
+			t.Fatalf("Up(0) again = %d, %v; expected nothing applied", n, err)
+		}
+		if n, err := m.Down(ctx, 2); err != nil || n != 2 {
+			t.Fatalf("Down(2) = %d, %v; expected 2 reverted", n, err)
+		}
+		if got, want := strings.Join(log, ","), "up1,up2,up3,down2"; got != want {
+			t.Errorf("steps = %s; expected %s", got, want)
+		}
+		if _, ok := store.applied[1]; !ok || len(store.applied) != 1 {
+			t.Errorf("applied = %v; expected only version 1", store.applied)
+		}
+	})
+
+	t.Run("stops at failing migration", func(t *testing.T) {
+		var log []string
+		m, store := newTestMigrator(&log, 2)
+
+		n, err := m.Up(ctx, 0)
+		if err == nil || n != 1 {
+			t.Fatalf("Up = %d, %v; expected 1 applied and an error", n, err)
+		}
+		if _, ok := store.applied[2]; ok {
+			t.Error("failed migration 2 recorded as applied")
+		}
+		pending, err := m.Pending(ctx)
+		if err != nil || len(pending) != 2 || pending[0].Version != 2 {
+			t.Errorf("Pending = %v, %v; expected versions 2 and 3", pending, err)
+		}
+	})
+
+	t.Run("applied by another run meanwhile", func(t *testing.T) {
+		var log []string
+		m, store := newTestMigrator(&log)
+		other := m.migrations[1]
+		m.migrations[1].Up = func(ctx context.Context, database *db.Mongo) error {
+			return store.Record(ctx, other, time.Now())
+		}
+
+		n, err := m.Up(ctx, 0)
+		if err != nil || n != 2 {
+			t.Fatalf("Up = %d, %v; expected 2 applied", n, err)
+		}
+		if len(store.applied) != 3 {
+			t.Errorf("applied = %v; expected versions 1 to 3", store.applied)
+		}
+	})
+
+	t.Run("duplicate versions", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("NewMigrator did not panic on duplicate versions")
+			}
+		}()
+		NewMigrator(nil, &memoryStore{}, []Migration{{Version: 1}, {Version: 1}}, glog.New())
+	})
+
+	t.Run("all migrations", func(t *testing.T) {
+		for i, migration := range All {
+			if migration.Version != i+1 || migration.Up == nil || migration.Description == "" {
+				t.Errorf("migration %d = %+v; expected version %d with Up and a description", i, migration, i+1)
+			}
+		}
+	})
+}
+
+func TestDuplicateEmailsError(t *testing.T) {
+	if err := duplicateEmailsError(nil); err != nil {
+		t.Errorf("duplicateEmailsError(nil) = %v; expected nil", err)
+	}
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	err := duplicateEmailsError([]duplicateEmail{{Email: "lan@example.com", IDs: []primitive.ObjectID{a, b}}})
+	if err == nil || !strings.Contains(err.Error(), "lan@example.com: "+a.Hex()+", "+b.Hex()) {
+		t.Errorf("duplicateEmailsError() = %v; expected the email with both member ids", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	m, _ := newTestMigrator(&log)
+
+	var out bytes.Buffer
+	if err := Run(ctx, m, []string{"up", "1"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := Run(ctx, m, []string{"status"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 || lines[0] != "1 migration(s) applied" || !strings.HasPrefix(lines[1], "VERSION") {
+		t.Fatalf("output = %q", out.String())
+	}
+	if strings.Contains(lines[2], "pending") || !strings.Contains(lines[3], "pending") || !strings.Contains(lines[4], "three") {
+		t.Errorf("status = %q; expected version 1 applied, 2 and 3 pending", lines[2:])
+	}
+
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "zero"}, {"down", "0"}, {"status", "1"}, {"up", "1", "2"}} {
+		if err := Run(ctx, m, args, &out); err == nil {
+			t.Errorf("Run(%q) succeeded; expected usage error", args)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Collection is the collection recording the applied migrations
+const Collection = "schema_migrations"
+
+type record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// MongoStore record the applied migrations in the schema_migrations collection,
+// one document per migration with the version as id
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoStore(database *db.Mongo) *MongoStore {
+	return &MongoStore{
+		collection: database.Collection(Collection),
+	}
+}
+
+// Applied return the time each applied migration was applied at by version
+func (s *MongoStore) Applied(ctx context.Context) (map[int]time.Time, error) {
+	cursor, err := s.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	applied := map[int]time.Time{}
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+// Record mark a migration as applied, it returns db.ErrDuplicateKey when another run recorded it meanwhile
+func (s *MongoStore) Record(ctx context.Context, migration Migration, at time.Time) error {
+	_, err := s.collection.InsertOne(ctx, record{
+		Version:     migration.Version,
+		Description: migration.Description,
+		AppliedAt:   at,
+	})
+	return db.MongoError(err)
+}
+
+// Remove mark a migration as not applied
+func (s *MongoStore) Remove(ctx context.Context, version int) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": version})
+	return err
+}
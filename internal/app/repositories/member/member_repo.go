@@ -2,27 +2,29 @@ package member
 
 import (
 	"context"
+	"time"
 
+	"booking/internal/app/db"
 	"booking/internal/app/types"
 
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // MongoRepository is MongoDB implementation of repository
 type MongoRepository struct {
-	client *mongo.Client
+	db *db.Mongo
 }
 
-func NewMongoRepository(c *mongo.Client) *MongoRepository {
+func NewMongoRepository(m *db.Mongo) *MongoRepository {
 	return &MongoRepository{
-		client: c,
+		db: m,
 	}
 }
 
 func (r *MongoRepository) collection() *mongo.Collection {
-	return r.client.Database("booking").Collection("members")
+	return r.db.Collection("members")
 }
 
 // FindByID return member base on given id
@@ -31,24 +33,24 @@ func (r *MongoRepository) FindByID(ctx context.Context, id string) (*types.Membe
 	objectID, err := primitive.ObjectIDFromHex(id)
 
 	if err != nil {
-		return nil, err
+		return nil, db.MongoError(err)
 	}
 	var member *types.Member
 	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&member)
-	return member, err
+	return member, db.MongoError(err)
 }
 
 // Insert Member to DB Mongo
 func (r *MongoRepository) Insert(ctx context.Context, member types.Member) error {
-	_, err := r.collection().InsertOne(context.TODO(), member)
-	return err
+	_, err := r.collection().InsertOne(ctx, member)
+	return db.MongoError(err)
 }
 
 // Update Member by using ID
 func (r *MongoRepository) UpdateMemberByID(ctx context.Context, member types.UpdateMemberRequest) error {
 	memberId, err := primitive.ObjectIDFromHex(member.ID)
 	if err != nil {
-		return err
+		return db.MongoError(err)
 	}
 
 	updatedMember := bson.M{"$set": bson.M{
@@ -56,11 +58,73 @@ func (r *MongoRepository) UpdateMemberByID(ctx context.Context, member types.Upd
 	}}
 
 	_, err = r.collection().UpdateByID(ctx, memberId, updatedMember)
-	return err
+	return db.MongoError(err)
 }
 
 func (r *MongoRepository) FindByEmail(ctx context.Context, email string) (*types.Member, error) {
 	var user *types.Member
 	err := r.collection().FindOne(ctx, bson.M{"email": email}).Decode(&user)
-	return user, err
+	return user, db.MongoError(err)
+}
+
+// UpdateStatus change the account status of a member
+func (r *MongoRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status types.MemberStatus) error {
+	_, err := r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{"status": status}})
+	return db.MongoError(err)
+}
+
+// RecordFailedLogin count a failed login of a member
+func (r *MongoRepository) RecordFailedLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection().UpdateByID(ctx, id, bson.M{
+		"$inc": bson.M{"failed_logins": 1},
+		"$set": bson.M{"last_failed_login": at},
+	})
+	return db.MongoError(err)
+}
+
+// ResetFailedLogins forget the failed logins of a member, unlocking the account
+func (r *MongoRepository) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection().UpdateByID(ctx, id, bson.M{
+		"$set":   bson.M{"failed_logins": 0},
+		"$unset": bson.M{"last_failed_login": ""},
+	})
+	return db.MongoError(err)
+}
+
+// UpdateTOTP set the TOTP secret, state and hashed recovery codes of a member
+func (r *MongoRepository) UpdateTOTP(ctx context.Context, id primitive.ObjectID, secret string, enabled bool, recoveryCodes []string) error {
+	_, err := r.collection().UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"totp_secret":    secret,
+		"totp_enabled":   enabled,
+		"recovery_codes": recoveryCodes,
+	}})
+	return db.MongoError(err)
+}
+
+// UseTOTPStep record the time step of a TOTP code used by a member, it return false
+// if a code of the same or a later step was already used so codes can't be replayed
+func (r *MongoRepository) UseTOTPStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	filter := bson.M{
+		"_id": id,
+		"$or": []bson.M{
+			{"totp_last_step": bson.M{"$lt": step}},
+			{"totp_last_step": bson.M{"$exists": false}},
+		},
+	}
+	res, err := r.collection().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"totp_last_step": step}})
+	if err != nil {
+		return false, db.MongoError(err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// UseRecoveryCode remove the recovery code with given hash of a member,
+// it return false if the member has no such code
+func (r *MongoRepository) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	filter := bson.M{"_id": id, "recovery_codes": hash}
+	res, err := r.collection().UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"recovery_codes": hash}})
+	if err != nil {
+		return false, db.MongoError(err)
+	}
+	return res.ModifiedCount == 1, nil
 }
@@ -0,0 +1,165 @@
+package member
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is in-memory implementation of repository
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	members map[primitive.ObjectID]types.Member
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		members: map[primitive.ObjectID]types.Member{},
+	}
+}
+
+// FindByID return member base on given id
+func (r *MemoryRepository) FindByID(ctx context.Context, id string) (*types.Member, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.ErrNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	member, ok := r.members[objectID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return copyMember(member), nil
+}
+
+// Insert Member in memory
+func (r *MemoryRepository) Insert(ctx context.Context, member types.Member) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.members[member.ID]; ok {
+		return db.ErrDuplicateKey
+	}
+	for _, m := range r.members {
+		if m.Email == member.Email {
+			return db.ErrDuplicateKey
+		}
+	}
+	r.members[member.ID] = *copyMember(member)
+	return nil
+}
+
+// Update Member by using ID
+func (r *MemoryRepository) UpdateMemberByID(ctx context.Context, member types.UpdateMemberRequest) error {
+	memberId, err := primitive.ObjectIDFromHex(member.ID)
+	if err != nil {
+		return db.ErrNotFound
+	}
+
+	r.update(memberId, func(m *types.Member) {
+		m.Password = member.Password
+	})
+	return nil
+}
+
+func (r *MemoryRepository) FindByEmail(ctx context.Context, email string) (*types.Member, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, member := range r.members {
+		if member.Email == email {
+			return copyMember(member), nil
+		}
+	}
+	return nil, db.ErrNotFound
+}
+
+// UpdateStatus change the account status of a member
+func (r *MemoryRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status types.MemberStatus) error {
+	r.update(id, func(m *types.Member) {
+		m.Status = status
+	})
+	return nil
+}
+
+// RecordFailedLogin count a failed login of a member
+func (r *MemoryRepository) RecordFailedLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.update(id, func(m *types.Member) {
+		m.FailedLogins++
+		m.LastFailedLogin = at
+	})
+	return nil
+}
+
+// ResetFailedLogins forget the failed logins of a member, unlocking the account
+func (r *MemoryRepository) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	r.update(id, func(m *types.Member) {
+		m.FailedLogins = 0
+		m.LastFailedLogin = time.Time{}
+	})
+	return nil
+}
+
+// UpdateTOTP set the TOTP secret, state and hashed recovery codes of a member
+func (r *MemoryRepository) UpdateTOTP(ctx context.Context, id primitive.ObjectID, secret string, enabled bool, recoveryCodes []string) error {
+	r.update(id, func(m *types.Member) {
+		m.TOTPSecret = secret
+		m.TOTPEnabled = enabled
+		m.RecoveryCodes = append([]string(nil), recoveryCodes...)
+	})
+	return nil
+}
+
+// UseTOTPStep record the time step of a TOTP code used by a member, it return false
+// if a code of the same or a later step was already used so codes can't be replayed
+func (r *MemoryRepository) UseTOTPStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	used := false
+	r.update(id, func(m *types.Member) {
+		if m.TOTPLastStep < step {
+			m.TOTPLastStep = step
+			used = true
+		}
+	})
+	return used, nil
+}
+
+// UseRecoveryCode remove the recovery code with given hash of a member,
+// it return false if the member has no such code
+func (r *MemoryRepository) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	used := false
+	r.update(id, func(m *types.Member) {
+		codes := m.RecoveryCodes[:0]
+		for _, code := range m.RecoveryCodes {
+			if code == hash {
+				used = true
+				continue
+			}
+			codes = append(codes, code)
+		}
+		m.RecoveryCodes = codes
+	})
+	return used, nil
+}
+
+// update apply change to the member with given id, unknown ids are ignored like Mongo updates
+func (r *MemoryRepository) update(id primitive.ObjectID, change func(m *types.Member)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	member, ok := r.members[id]
+	if !ok {
+		return
+	}
+	change(&member)
+	r.members[id] = member
+}
+
+// copyMember return a copy of the member not sharing its recovery codes
+func copyMember(member types.Member) *types.Member {
+	member.RecoveryCodes = append([]string(nil), member.RecoveryCodes...)
+	return &member
+}
@@ -0,0 +1,14 @@
+package member
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	memberServices "booking/internal/app/services/member"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.MemberRepository(t, func(t *testing.T) memberServices.Repository {
+		return NewMemoryRepository()
+	})
+}
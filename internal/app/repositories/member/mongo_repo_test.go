@@ -0,0 +1,26 @@
+//go:build integration
+// +build integration
+
+package member
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/migrations"
+	"booking/internal/app/repositories/repotest"
+	memberServices "booking/internal/app/services/member"
+	"booking/internal/pkg/glog"
+)
+
+func TestMongoRepository(t *testing.T) {
+	database := repotest.Mongo(t)
+	migrator := migrations.New(database, glog.New())
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewMongoRepository(database)
+	repotest.MemberRepository(t, func(t *testing.T) memberServices.Repository {
+		return repo
+	})
+}
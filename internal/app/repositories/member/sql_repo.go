@@ -0,0 +1,179 @@
+package member
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const memberColumns = "id, name, password, email, role, status, failed_logins, last_failed_login, totp_secret, totp_enabled, totp_last_step"
+
+// SQLRepository is database/sql implementation of repository
+type SQLRepository struct {
+	db *db.SQL
+}
+
+func NewSQLRepository(s *db.SQL) *SQLRepository {
+	return &SQLRepository{
+		db: s,
+	}
+}
+
+// CreateTables create the tables of members if they do not exist
+func (r *SQLRepository) CreateTables(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, r.db.Schema(`CREATE TABLE IF NOT EXISTS members (
+		id CHAR(24) PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		password VARCHAR(255) NOT NULL,
+		email VARCHAR(255) NOT NULL UNIQUE,
+		role VARCHAR(32) NOT NULL,
+		status VARCHAR(32) NOT NULL,
+		failed_logins INTEGER NOT NULL DEFAULT 0,
+		last_failed_login {time} NULL,
+		totp_secret VARCHAR(64) NOT NULL DEFAULT '',
+		totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+		totp_last_step BIGINT NOT NULL DEFAULT 0
+	)`))
+	if err != nil {
+		return r.db.Error(err)
+	}
+	_, err = r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS member_recovery_codes (
+		member_id CHAR(24) NOT NULL,
+		hash CHAR(64) NOT NULL,
+		PRIMARY KEY (member_id, hash)
+	)`)
+	return r.db.Error(err)
+}
+
+// FindByID return member base on given id
+func (r *SQLRepository) FindByID(ctx context.Context, id string) (*types.Member, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, r.db.Error(err)
+	}
+	return r.findOne(ctx, "SELECT "+memberColumns+" FROM members WHERE id = ?", id)
+}
+
+// Insert Member to SQL database
+func (r *SQLRepository) Insert(ctx context.Context, member types.Member) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("INSERT INTO members ("+memberColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+		member.ID.Hex(), member.Name, member.Password, member.Email, member.Role, member.Status,
+		member.FailedLogins, db.NullTime(member.LastFailedLogin.UTC()), member.TOTPSecret, member.TOTPEnabled, member.TOTPLastStep)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	return r.insertRecoveryCodes(ctx, r.db.DB, member.ID, member.RecoveryCodes)
+}
+
+// Update Member by using ID
+func (r *SQLRepository) UpdateMemberByID(ctx context.Context, member types.UpdateMemberRequest) error {
+	if _, err := primitive.ObjectIDFromHex(member.ID); err != nil {
+		return r.db.Error(err)
+	}
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE members SET password = ? WHERE id = ?"), member.Password, member.ID)
+	return r.db.Error(err)
+}
+
+func (r *SQLRepository) FindByEmail(ctx context.Context, email string) (*types.Member, error) {
+	return r.findOne(ctx, "SELECT "+memberColumns+" FROM members WHERE email = ?", email)
+}
+
+// UpdateStatus change the account status of a member
+func (r *SQLRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status types.MemberStatus) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE members SET status = ? WHERE id = ?"), status, id.Hex())
+	return r.db.Error(err)
+}
+
+// RecordFailedLogin count a failed login of a member
+func (r *SQLRepository) RecordFailedLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE members SET failed_logins = failed_logins + 1, last_failed_login = ? WHERE id = ?"), at.UTC(), id.Hex())
+	return r.db.Error(err)
+}
+
+// ResetFailedLogins forget the failed logins of a member, unlocking the account
+func (r *SQLRepository) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE members SET failed_logins = 0, last_failed_login = NULL WHERE id = ?"), id.Hex())
+	return r.db.Error(err)
+}
+
+// UpdateTOTP set the TOTP secret, state and hashed recovery codes of a member
+func (r *SQLRepository) UpdateTOTP(ctx context.Context, id primitive.ObjectID, secret string, enabled bool, recoveryCodes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.db.Rebind("UPDATE members SET totp_secret = ?, totp_enabled = ? WHERE id = ?"), secret, enabled, id.Hex()); err != nil {
+		return r.db.Error(err)
+	}
+	if _, err := tx.ExecContext(ctx, r.db.Rebind("DELETE FROM member_recovery_codes WHERE member_id = ?"), id.Hex()); err != nil {
+		return r.db.Error(err)
+	}
+	if err := r.insertRecoveryCodes(ctx, tx, id, recoveryCodes); err != nil {
+		return r.db.Error(err)
+	}
+	return tx.Commit()
+}
+
+// UseTOTPStep record the time step of a TOTP code used by a member, it return false
+// if a code of the same or a later step was already used so codes can't be replayed
+func (r *SQLRepository) UseTOTPStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE members SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?"), step, id.Hex(), step)
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, r.db.Error(err)
+}
+
+// UseRecoveryCode remove the recovery code with given hash of a member,
+// it return false if the member has no such code
+func (r *SQLRepository) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM member_recovery_codes WHERE member_id = ? AND hash = ?"), id.Hex(), hash)
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, r.db.Error(err)
+}
+
+func (r *SQLRepository) insertRecoveryCodes(ctx context.Context, e db.Execer, id primitive.ObjectID, hashes []string) error {
+	for _, hash := range hashes {
+		if _, err := e.ExecContext(ctx, r.db.Rebind("INSERT INTO member_recovery_codes (member_id, hash) VALUES (?, ?)"), id.Hex(), hash); err != nil {
+			return r.db.Error(err)
+		}
+	}
+	return nil
+}
+
+// findOne return the member selected by query with its recovery codes
+func (r *SQLRepository) findOne(ctx context.Context, query string, args ...interface{}) (*types.Member, error) {
+	var member types.Member
+	var id string
+	var lastFailedLogin db.Time
+	err := r.db.QueryRowContext(ctx, r.db.Rebind(query), args...).Scan(&id, &member.Name, &member.Password, &member.Email,
+		&member.Role, &member.Status, &member.FailedLogins, &lastFailedLogin, &member.TOTPSecret, &member.TOTPEnabled, &member.TOTPLastStep)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	member.ID, _ = primitive.ObjectIDFromHex(id)
+	member.LastFailedLogin = time.Time(lastFailedLogin)
+
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind("SELECT hash FROM member_recovery_codes WHERE member_id = ? ORDER BY hash"), id)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, r.db.Error(err)
+		}
+		member.RecoveryCodes = append(member.RecoveryCodes, hash)
+	}
+	return &member, rows.Err()
+}
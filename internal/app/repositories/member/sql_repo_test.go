@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package member
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	memberServices "booking/internal/app/services/member"
+)
+
+func TestSQLRepository(t *testing.T) {
+	repotest.MemberRepository(t, func(t *testing.T) memberServices.Repository {
+		repo := NewSQLRepository(repotest.SQLite(t))
+		if err := repo.CreateTables(context.Background()); err != nil {
+			t.Fatalf("failed to create tables, err: %v", err)
+		}
+		return repo
+	})
+}
@@ -0,0 +1,176 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"booking/internal/app/db"
+	memberServices "booking/internal/app/services/member"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newMember() types.Member {
+	return types.Member{
+		ID:       primitive.NewObjectID(),
+		Name:     "Guest",
+		Password: "hashed-password",
+		Email:    uniqueEmail(),
+		Role:     types.RoleGuest,
+		Status:   types.MemberStatusActive,
+	}
+}
+
+// MemberRepository run the conformance suite of member repositories,
+// newRepo is called for every test
+func MemberRepository(t *testing.T, newRepo func(t *testing.T) memberServices.Repository) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.FindByID(ctx, primitive.NewObjectID().Hex())
+		assertNotFound(t, err)
+		_, err = repo.FindByEmail(ctx, uniqueEmail())
+		assertNotFound(t, err)
+		_, err = repo.FindByID(ctx, "not-an-id")
+		assertNotFound(t, err)
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		repo := newRepo(t)
+		member := newMember()
+		if err := repo.Insert(ctx, member); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+
+		byID, err := repo.FindByID(ctx, member.ID.Hex())
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		byEmail, err := repo.FindByEmail(ctx, member.Email)
+		if err != nil {
+			t.Fatalf("FindByEmail() error = %v", err)
+		}
+		for _, got := range []*types.Member{byID, byEmail} {
+			if got.ID != member.ID || got.Email != member.Email || got.Name != member.Name ||
+				got.Password != member.Password || got.Role != member.Role || got.Status != member.Status {
+				t.Errorf("found %+v; expected %+v", got, member)
+			}
+		}
+	})
+
+	t.Run("duplicate email", func(t *testing.T) {
+		repo := newRepo(t)
+		member := newMember()
+		if err := repo.Insert(ctx, member); err != nil {
+			t.Fatal(err)
+		}
+		other := newMember()
+		other.Email = member.Email
+		if err := repo.Insert(ctx, other); !errors.Is(err, db.ErrDuplicateKey) {
+			t.Errorf("Insert() of duplicate email error = %v; expected %v", err, db.ErrDuplicateKey)
+		}
+		if err := repo.Insert(ctx, member); !errors.Is(err, db.ErrDuplicateKey) {
+			t.Errorf("Insert() of duplicate id error = %v; expected %v", err, db.ErrDuplicateKey)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepo(t)
+		member := newMember()
+		if err := repo.Insert(ctx, member); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.UpdateMemberByID(ctx, types.UpdateMemberRequest{ID: member.ID.Hex(), Password: "new-password"}); err != nil {
+			t.Fatalf("UpdateMemberByID() error = %v", err)
+		}
+		if err := repo.UpdateStatus(ctx, member.ID, types.MemberStatusPending); err != nil {
+			t.Fatalf("UpdateStatus() error = %v", err)
+		}
+		got, err := repo.FindByID(ctx, member.ID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Password != "new-password" || got.Status != types.MemberStatusPending {
+			t.Errorf("updated member = %+v; expected new password and pending status", got)
+		}
+		if got.Name != member.Name || got.Email != member.Email || got.Role != member.Role {
+			t.Errorf("update changed other fields: %+v", got)
+		}
+
+		// updates of unknown members are no-ops
+		if err := repo.UpdateMemberByID(ctx, types.UpdateMemberRequest{ID: primitive.NewObjectID().Hex(), Password: "x"}); err != nil {
+			t.Errorf("UpdateMemberByID() of unknown member error = %v", err)
+		}
+		if err := repo.UpdateMemberByID(ctx, types.UpdateMemberRequest{ID: "not-an-id", Password: "x"}); err == nil {
+			t.Errorf("UpdateMemberByID() of invalid id succeeded")
+		}
+	})
+
+	t.Run("failed logins", func(t *testing.T) {
+		repo := newRepo(t)
+		member := newMember()
+		if err := repo.Insert(ctx, member); err != nil {
+			t.Fatal(err)
+		}
+
+		at := now()
+		repo.RecordFailedLogin(ctx, member.ID, at.Add(-time.Minute))
+		if err := repo.RecordFailedLogin(ctx, member.ID, at); err != nil {
+			t.Fatalf("RecordFailedLogin() error = %v", err)
+		}
+		got, _ := repo.FindByID(ctx, member.ID.Hex())
+		if got.FailedLogins != 2 {
+			t.Errorf("FailedLogins = %d; expected 2", got.FailedLogins)
+		}
+		assertTime(t, "LastFailedLogin", got.LastFailedLogin, at)
+
+		if err := repo.ResetFailedLogins(ctx, member.ID); err != nil {
+			t.Fatalf("ResetFailedLogins() error = %v", err)
+		}
+		got, _ = repo.FindByID(ctx, member.ID.Hex())
+		if got.FailedLogins != 0 || !got.LastFailedLogin.IsZero() {
+			t.Errorf("after reset FailedLogins = %d, LastFailedLogin = %v; expected none", got.FailedLogins, got.LastFailedLogin)
+		}
+	})
+
+	t.Run("two-factor", func(t *testing.T) {
+		repo := newRepo(t)
+		member := newMember()
+		if err := repo.Insert(ctx, member); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.UpdateTOTP(ctx, member.ID, "SECRET", true, []string{"a", "b"}); err != nil {
+			t.Fatalf("UpdateTOTP() error = %v", err)
+		}
+		got, _ := repo.FindByID(ctx, member.ID.Hex())
+		if got.TOTPSecret != "SECRET" || !got.TOTPEnabled || len(got.RecoveryCodes) != 2 {
+			t.Errorf("member after UpdateTOTP() = %+v", got)
+		}
+
+		for _, tc := range []struct {
+			step int64
+			want bool
+		}{{10, true}, {10, false}, {9, false}, {11, true}} {
+			if used, err := repo.UseTOTPStep(ctx, member.ID, tc.step); err != nil || used != tc.want {
+				t.Errorf("UseTOTPStep(%d) = %v, %v; expected %v", tc.step, used, err, tc.want)
+			}
+		}
+
+		if used, err := repo.UseRecoveryCode(ctx, member.ID, "a"); err != nil || !used {
+			t.Errorf("UseRecoveryCode() = %v, %v; expected true", used, err)
+		}
+		if used, err := repo.UseRecoveryCode(ctx, member.ID, "a"); err != nil || used {
+			t.Errorf("UseRecoveryCode() twice = %v, %v; expected false", used, err)
+		}
+		got, _ = repo.FindByID(ctx, member.ID.Hex())
+		if len(got.RecoveryCodes) != 1 || got.RecoveryCodes[0] != "b" {
+			t.Errorf("RecoveryCodes = %v; expected [b]", got.RecoveryCodes)
+		}
+	})
+}
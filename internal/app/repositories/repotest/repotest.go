@@ -0,0 +1,80 @@
+// Package repotest hold the conformance suites every repository implementation must pass,
+// so the in-memory and database backends behave the same for the services using them
+package repotest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/db"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// now return the current time with the precision kept by every backend
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// uniqueEmail return an email address not used by previous runs against a shared database
+func uniqueEmail() string {
+	return primitive.NewObjectID().Hex() + "@booking.local"
+}
+
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	if apperr.KindOf(err) != apperr.NotFound {
+		t.Errorf("error = %v; expected not found", err)
+	}
+}
+
+func assertTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("%s = %v; expected %v", name, got, want)
+	}
+}
+
+// Mongo return the database MONGODB_DATABASE, booking_test by default, of the MongoDB server at
+// MONGODB_URI, mongodb://localhost:27017 by default. Suites only add documents with new ids so
+// the database can be shared.
+func Mongo(t *testing.T) *db.Mongo {
+	t.Helper()
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
+	name := os.Getenv("MONGODB_DATABASE")
+	if name == "" {
+		name = "booking_test"
+	}
+
+	database, err := db.ConnectMongo(context.Background(), configs.MongoDB{Address: uri, Database: name}, glog.New())
+	if err != nil {
+		t.Fatalf("failed to connect to %v, err: %v", uri, err)
+	}
+	t.Cleanup(func() {
+		database.Disconnect(context.Background())
+	})
+	return database
+}
+
+// SQLite return a connection to a new SQLite database removed after the test
+func SQLite(t *testing.T) *db.SQL {
+	t.Helper()
+	dsn := "file:" + filepath.Join(t.TempDir(), "booking.db") + "?_foreign_keys=on"
+	conn, err := db.OpenSQL(db.TypeSQLite, dsn)
+	if err != nil {
+		t.Fatalf("failed to open %v, err: %v", dsn, err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn
+}
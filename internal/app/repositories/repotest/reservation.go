@@ -0,0 +1,154 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"booking/internal/app/db"
+	reservationServices "booking/internal/app/services/reservation"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newReservation(tableID primitive.ObjectID, start time.Time, duration time.Duration) types.Reservation {
+	return types.Reservation{
+		ID:        primitive.NewObjectID(),
+		TableID:   tableID,
+		MemberID:  primitive.NewObjectID(),
+		Name:      "Guest",
+		Phone:     "0900000000",
+		PartySize: 2,
+		StartTime: start,
+		EndTime:   start.Add(duration),
+		CreateAt:  now(),
+		UpdateAt:  now(),
+	}
+}
+
+// ReservationRepository run the conformance suite of reservation repositories,
+// newRepo is called for every test
+func ReservationRepository(t *testing.T, newRepo func(t *testing.T) reservationServices.Repository) {
+	ctx := context.Background()
+	day := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time {
+		return day.Add(time.Duration(hour) * time.Hour)
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.FindByID(ctx, primitive.NewObjectID().Hex())
+		assertNotFound(t, err)
+		_, err = repo.FindByID(ctx, "not-an-id")
+		assertNotFound(t, err)
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		repo := newRepo(t)
+		tableID := primitive.NewObjectID()
+		late, early := newReservation(tableID, at(20), time.Hour), newReservation(tableID, at(18), time.Hour)
+		other := newReservation(primitive.NewObjectID(), at(19), time.Hour)
+		for _, reservation := range []types.Reservation{late, early, other} {
+			if err := repo.Insert(ctx, reservation); err != nil {
+				t.Fatalf("Insert() error = %v", err)
+			}
+		}
+
+		got, err := repo.FindByID(ctx, early.ID.Hex())
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if got.TableID != tableID || got.MemberID != early.MemberID || got.Name != early.Name || got.PartySize != early.PartySize {
+			t.Errorf("FindByID() = %+v; expected %+v", got, early)
+		}
+		assertTime(t, "StartTime", got.StartTime, early.StartTime)
+		assertTime(t, "EndTime", got.EndTime, early.EndTime)
+
+		tests := []struct {
+			name   string
+			filter types.ReservationFilter
+			want   []primitive.ObjectID
+		}{
+			{"by table ordered by start", types.ReservationFilter{TableID: tableID.Hex()}, []primitive.ObjectID{early.ID, late.ID}},
+			{"by tables", types.ReservationFilter{TableIDs: []string{tableID.Hex(), other.TableID.Hex()}}, []primitive.ObjectID{early.ID, other.ID, late.ID}},
+			{"by tables and period", types.ReservationFilter{TableIDs: []string{other.TableID.Hex(), primitive.NewObjectID().Hex()}, From: at(18), To: at(22)}, []primitive.ObjectID{other.ID}},
+			{"by member", types.ReservationFilter{MemberID: late.MemberID.Hex()}, []primitive.ObjectID{late.ID}},
+			{"overlapping period", types.ReservationFilter{TableID: tableID.Hex(), From: at(19), To: at(21)}, []primitive.ObjectID{late.ID}},
+			{"touching period", types.ReservationFilter{TableID: tableID.Hex(), From: at(19), To: at(20)}, []primitive.ObjectID{}},
+		}
+		for _, tc := range tests {
+			reservations, err := repo.Find(ctx, tc.filter)
+			if err != nil {
+				t.Fatalf("Find() %s error = %v", tc.name, err)
+			}
+			ids := []primitive.ObjectID{}
+			for _, r := range reservations {
+				ids = append(ids, r.ID)
+			}
+			if len(ids) != len(tc.want) || (len(ids) > 0 && (ids[0] != tc.want[0] || ids[len(ids)-1] != tc.want[len(tc.want)-1])) {
+				t.Errorf("Find() %s = %v; expected %v", tc.name, ids, tc.want)
+			}
+		}
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		repo := newRepo(t)
+		tableID := primitive.NewObjectID()
+		reservation := newReservation(tableID, at(18), 2*time.Hour)
+		if err := repo.Insert(ctx, reservation); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Insert(ctx, newReservation(tableID, at(19), time.Hour)); !errors.Is(err, db.ErrOverlap) {
+			t.Errorf("Insert() of overlapping reservation error = %v; expected %v", err, db.ErrOverlap)
+		}
+		if err := repo.Insert(ctx, newReservation(primitive.NewObjectID(), at(19), time.Hour)); err != nil {
+			t.Errorf("Insert() on another table error = %v", err)
+		}
+		next := newReservation(tableID, at(20), time.Hour)
+		if err := repo.Insert(ctx, next); err != nil {
+			t.Errorf("Insert() of reservation starting at the end error = %v", err)
+		}
+
+		next.StartTime = at(19)
+		if err := repo.Update(ctx, next); !errors.Is(err, db.ErrOverlap) {
+			t.Errorf("Update() into overlap error = %v; expected %v", err, db.ErrOverlap)
+		}
+		reservation.EndTime = at(19)
+		reservation.Note = "shorter"
+		if err := repo.Update(ctx, reservation); err != nil {
+			t.Errorf("Update() of itself error = %v", err)
+		}
+		got, _ := repo.FindByID(ctx, reservation.ID.Hex())
+		assertTime(t, "EndTime", got.EndTime, at(19))
+		if got.Note != "shorter" || got.MemberID != reservation.MemberID {
+			t.Errorf("updated reservation = %+v", got)
+		}
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		repo := newRepo(t)
+		tableID := primitive.NewObjectID()
+		reservation := newReservation(tableID, at(18), 2*time.Hour)
+		if err := repo.Insert(ctx, reservation); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.Delete(ctx, reservation.ID.Hex()); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		reservations, _ := repo.Find(ctx, types.ReservationFilter{TableID: tableID.Hex()})
+		if len(reservations) != 0 {
+			t.Errorf("Find() after delete = %+v; expected no reservation", reservations)
+		}
+		got, err := repo.FindByID(ctx, reservation.ID.Hex())
+		if err != nil || !got.DelFlg {
+			t.Errorf("FindByID() after delete = %+v, %v; expected the reservation flagged deleted", got, err)
+		}
+		if err := repo.Insert(ctx, newReservation(tableID, at(18), time.Hour)); err != nil {
+			t.Errorf("Insert() over deleted reservation error = %v", err)
+		}
+	})
+}
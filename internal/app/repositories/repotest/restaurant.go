@@ -0,0 +1,122 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"booking/internal/app/db"
+	restaurantServices "booking/internal/app/services/restaurant"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newRestaurant(name string) types.Restaurant {
+	return types.Restaurant{
+		ID:       primitive.NewObjectID(),
+		Name:     name,
+		Address:  "1 Le Loi",
+		CreateAt: now(),
+		UpdateAt: now(),
+	}
+}
+
+// findRestaurant return the restaurant with given id in the list
+func findRestaurant(restaurants []types.Restaurant, id primitive.ObjectID) (int, bool) {
+	for i, restaurant := range restaurants {
+		if restaurant.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// RestaurantRepository run the conformance suite of restaurant repositories,
+// newRepo is called for every test. FindAll may return restaurants of previous
+// runs against a shared database, only the inserted ones are checked.
+func RestaurantRepository(t *testing.T, newRepo func(t *testing.T) restaurantServices.Repository) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.FindByID(ctx, primitive.NewObjectID().Hex())
+		assertNotFound(t, err)
+		_, err = repo.FindByID(ctx, "not-an-id")
+		assertNotFound(t, err)
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		repo := newRepo(t)
+		second, first := newRestaurant("zz "+uniqueEmail()), newRestaurant("aa "+uniqueEmail())
+		for _, restaurant := range []types.Restaurant{second, first} {
+			if err := repo.Insert(ctx, restaurant); err != nil {
+				t.Fatalf("Insert() error = %v", err)
+			}
+		}
+		if err := repo.Insert(ctx, first); !errors.Is(err, db.ErrDuplicateKey) {
+			t.Errorf("Insert() of duplicate id error = %v; expected %v", err, db.ErrDuplicateKey)
+		}
+
+		got, err := repo.FindByID(ctx, first.ID.Hex())
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if got.Name != first.Name || got.Address != first.Address {
+			t.Errorf("FindByID() = %+v; expected %+v", got, first)
+		}
+
+		restaurants, err := repo.FindAll(ctx)
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		i, okFirst := findRestaurant(restaurants, first.ID)
+		j, okSecond := findRestaurant(restaurants, second.ID)
+		if !okFirst || !okSecond || i > j {
+			t.Errorf("FindAll() = %+v; expected %v before %v", restaurants, first.Name, second.Name)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepo(t)
+		restaurant := newRestaurant("Pho")
+		if err := repo.Insert(ctx, restaurant); err != nil {
+			t.Fatal(err)
+		}
+
+		req := types.UpdateRestaurantRequest{ID: restaurant.ID.Hex(), Name: "Bun", Address: "2 Le Loi"}
+		if err := repo.UpdateRestaurantByID(ctx, req); err != nil {
+			t.Fatalf("UpdateRestaurantByID() error = %v", err)
+		}
+		got, _ := repo.FindByID(ctx, restaurant.ID.Hex())
+		if got.Name != req.Name || got.Address != req.Address || got.UpdateAt.Before(restaurant.UpdateAt) {
+			t.Errorf("updated restaurant = %+v; expected %+v", got, req)
+		}
+		assertTime(t, "CreateAt", got.CreateAt, restaurant.CreateAt)
+
+		req.ID = primitive.NewObjectID().Hex()
+		if err := repo.UpdateRestaurantByID(ctx, req); err != nil {
+			t.Errorf("UpdateRestaurantByID() of unknown restaurant error = %v", err)
+		}
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		repo := newRepo(t)
+		restaurant := newRestaurant("Pho")
+		if err := repo.Insert(ctx, restaurant); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.DeleteRestaurant(ctx, types.DeleteRestaurantRequest{ID: restaurant.ID.Hex(), DelFlg: true}); err != nil {
+			t.Fatalf("DeleteRestaurant() error = %v", err)
+		}
+		restaurants, _ := repo.FindAll(ctx)
+		if _, ok := findRestaurant(restaurants, restaurant.ID); ok {
+			t.Errorf("FindAll() after delete lists the restaurant")
+		}
+		got, err := repo.FindByID(ctx, restaurant.ID.Hex())
+		if err != nil || !got.DelFlg {
+			t.Errorf("FindByID() after delete = %+v, %v; expected the restaurant flagged deleted", got, err)
+		}
+	})
+}
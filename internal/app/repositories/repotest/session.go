@@ -0,0 +1,93 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sessionServices "booking/internal/app/services/session"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newSession(memberID primitive.ObjectID) types.Session {
+	return types.Session{
+		ID:               primitive.NewObjectID(),
+		MemberID:         memberID,
+		RefreshTokenHash: "hash",
+		ExpiresAt:        now().Add(time.Hour),
+		CreateAt:         now(),
+		UpdateAt:         now(),
+	}
+}
+
+// SessionRepository run the conformance suite of session repositories,
+// newRepo is called for every test
+func SessionRepository(t *testing.T, newRepo func(t *testing.T) sessionServices.Repository) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.FindByID(ctx, primitive.NewObjectID().Hex())
+		assertNotFound(t, err)
+		if rotated, err := repo.Rotate(ctx, primitive.NewObjectID(), "hash", "new", now()); err != nil || rotated {
+			t.Errorf("Rotate() of unknown session = %v, %v; expected false", rotated, err)
+		}
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		repo := newRepo(t)
+		session := newSession(primitive.NewObjectID())
+		if err := repo.Insert(ctx, session); err != nil {
+			t.Fatal(err)
+		}
+
+		expiresAt := now().Add(2 * time.Hour)
+		if rotated, err := repo.Rotate(ctx, session.ID, "hash", "new", expiresAt); err != nil || !rotated {
+			t.Fatalf("Rotate() = %v, %v; expected true", rotated, err)
+		}
+		if rotated, _ := repo.Rotate(ctx, session.ID, "hash", "newer", expiresAt); rotated {
+			t.Errorf("Rotate() with old hash = true; expected false")
+		}
+		got, err := repo.FindByID(ctx, session.ID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.RefreshTokenHash != "new" || got.Revoked {
+			t.Errorf("rotated session = %+v", got)
+		}
+		assertTime(t, "ExpiresAt", got.ExpiresAt, expiresAt)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		repo := newRepo(t)
+		memberID := primitive.NewObjectID()
+		first, second, other := newSession(memberID), newSession(memberID), newSession(primitive.NewObjectID())
+		for _, session := range []types.Session{first, second, other} {
+			if err := repo.Insert(ctx, session); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := repo.Revoke(ctx, first.ID); err != nil {
+			t.Fatalf("Revoke() error = %v", err)
+		}
+		if rotated, _ := repo.Rotate(ctx, first.ID, "hash", "new", now()); rotated {
+			t.Errorf("Rotate() of revoked session = true; expected false")
+		}
+
+		if err := repo.RevokeByMember(ctx, memberID); err != nil {
+			t.Fatalf("RevokeByMember() error = %v", err)
+		}
+		for _, tc := range []struct {
+			session types.Session
+			revoked bool
+		}{{first, true}, {second, true}, {other, false}} {
+			got, _ := repo.FindByID(ctx, tc.session.ID.Hex())
+			if got.Revoked != tc.revoked {
+				t.Errorf("session %v revoked = %v; expected %v", tc.session.ID, got.Revoked, tc.revoked)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"booking/internal/app/db"
+	tableServices "booking/internal/app/services/table"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTable(restaurantID primitive.ObjectID) types.Table {
+	return types.Table{
+		ID:           primitive.NewObjectID(),
+		RestaurantID: restaurantID,
+		Status:       "free",
+		Type:         "indoor",
+		Slots:        4,
+		CreateAt:     now(),
+		UpdateAt:     now(),
+	}
+}
+
+// TableRepository run the conformance suite of table repositories,
+// newRepo is called for every test
+func TableRepository(t *testing.T, newRepo func(t *testing.T) tableServices.Repository) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.FindByID(ctx, primitive.NewObjectID().Hex())
+		assertNotFound(t, err)
+		tables, err := repo.FindByRestaurant(ctx, primitive.NewObjectID().Hex())
+		if err != nil || tables == nil || len(tables) != 0 {
+			t.Errorf("FindByRestaurant() of unknown restaurant = %v, %v; expected empty list", tables, err)
+		}
+	})
+
+	t.Run("insert and find", func(t *testing.T) {
+		repo := newRepo(t)
+		restaurantID := primitive.NewObjectID()
+		first, second := newTable(restaurantID), newTable(restaurantID)
+		for _, table := range []types.Table{first, second, newTable(primitive.NewObjectID())} {
+			if err := repo.Insert(ctx, table); err != nil {
+				t.Fatalf("Insert() error = %v", err)
+			}
+		}
+		if err := repo.Insert(ctx, first); !errors.Is(err, db.ErrDuplicateKey) {
+			t.Errorf("Insert() of duplicate id error = %v; expected %v", err, db.ErrDuplicateKey)
+		}
+
+		got, err := repo.FindByID(ctx, first.ID.Hex())
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if got.RestaurantID != restaurantID || got.Status != first.Status || got.Type != first.Type || got.Slots != first.Slots {
+			t.Errorf("FindByID() = %+v; expected %+v", got, first)
+		}
+		assertTime(t, "CreateAt", got.CreateAt, first.CreateAt)
+
+		tables, err := repo.FindByRestaurant(ctx, restaurantID.Hex())
+		if err != nil {
+			t.Fatalf("FindByRestaurant() error = %v", err)
+		}
+		if len(tables) != 2 || tables[0].ID != first.ID || tables[1].ID != second.ID {
+			t.Errorf("FindByRestaurant() = %+v; expected tables %v and %v", tables, first.ID, second.ID)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepo(t)
+		table := newTable(primitive.NewObjectID())
+		if err := repo.Insert(ctx, table); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.UpdateTableByID(ctx, types.UpdateTableRequest{ID: table.ID.Hex(), Status: "busy"}); err != nil {
+			t.Fatalf("UpdateTableByID() error = %v", err)
+		}
+		got, _ := repo.FindByID(ctx, table.ID.Hex())
+		if got.Status != "busy" || got.Slots != table.Slots || got.Type != table.Type {
+			t.Errorf("updated table = %+v; expected only status changed", got)
+		}
+
+		if err := repo.UpdateTableByID(ctx, types.UpdateTableRequest{ID: primitive.NewObjectID().Hex(), Status: "busy"}); err != nil {
+			t.Errorf("UpdateTableByID() of unknown table error = %v", err)
+		}
+		if err := repo.UpdateTableByID(ctx, types.UpdateTableRequest{ID: "not-an-id", Status: "busy"}); err == nil {
+			t.Errorf("UpdateTableByID() of invalid id succeeded")
+		}
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		repo := newRepo(t)
+		table := newTable(primitive.NewObjectID())
+		if err := repo.Insert(ctx, table); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repo.DeleteTable(ctx, types.DeleteTableRequest{ID: table.ID.Hex(), DelFlg: true}); err != nil {
+			t.Fatalf("DeleteTable() error = %v", err)
+		}
+		tables, _ := repo.FindByRestaurant(ctx, table.RestaurantID.Hex())
+		if len(tables) != 0 {
+			t.Errorf("FindByRestaurant() after delete = %+v; expected no table", tables)
+		}
+		got, err := repo.FindByID(ctx, table.ID.Hex())
+		if err != nil || !got.DelFlg {
+			t.Errorf("FindByID() after delete = %+v, %v; expected the table flagged deleted", got, err)
+		}
+	})
+}
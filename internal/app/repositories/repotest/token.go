@@ -0,0 +1,60 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	memberServices "booking/internal/app/services/member"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newToken(purpose types.TokenPurpose, expiresAt time.Time) types.MemberToken {
+	return types.MemberToken{
+		ID:        primitive.NewObjectID(),
+		MemberID:  primitive.NewObjectID(),
+		Purpose:   purpose,
+		Hash:      primitive.NewObjectID().Hex(),
+		ExpiresAt: expiresAt,
+		CreateAt:  now(),
+	}
+}
+
+// TokenRepository run the conformance suite of member token repositories,
+// newRepo is called for every test
+func TokenRepository(t *testing.T, newRepo func(t *testing.T) memberServices.TokenRepository) {
+	ctx := context.Background()
+
+	t.Run("consume once", func(t *testing.T) {
+		repo := newRepo(t)
+		token := newToken(types.TokenPurposeVerifyEmail, now().Add(time.Hour))
+		if err := repo.Insert(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repo.Consume(ctx, token.Hash, types.TokenPurposeResetPassword); err == nil {
+			t.Errorf("Consume() for another purpose succeeded")
+		}
+		got, err := repo.Consume(ctx, token.Hash, token.Purpose)
+		if err != nil {
+			t.Fatalf("Consume() error = %v", err)
+		}
+		if got.ID != token.ID || got.MemberID != token.MemberID {
+			t.Errorf("Consume() = %+v; expected %+v", got, token)
+		}
+		_, err = repo.Consume(ctx, token.Hash, token.Purpose)
+		assertNotFound(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		repo := newRepo(t)
+		token := newToken(types.TokenPurposeResetPassword, now().Add(-time.Minute))
+		if err := repo.Insert(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+		_, err := repo.Consume(ctx, token.Hash, token.Purpose)
+		assertNotFound(t, err)
+	})
+}
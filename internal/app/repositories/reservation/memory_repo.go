@@ -0,0 +1,153 @@
+package reservation
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is in-memory implementation of repository
+type MemoryRepository struct {
+	mu           sync.RWMutex
+	reservations map[primitive.ObjectID]types.Reservation
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		reservations: map[primitive.ObjectID]types.Reservation{},
+	}
+}
+
+// FindByID return reservation base on given id
+func (r *MemoryRepository) FindByID(ctx context.Context, id string) (*types.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.ErrNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reservation, ok := r.reservations[objectID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &reservation, nil
+}
+
+// Find return not deleted reservations matching given filter, ordered by start time
+func (r *MemoryRepository) Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error) {
+	var tableID, memberID primitive.ObjectID
+	var err error
+	if filter.TableID != "" {
+		if tableID, err = primitive.ObjectIDFromHex(filter.TableID); err != nil {
+			return nil, db.ErrNotFound
+		}
+	}
+	if filter.MemberID != "" {
+		if memberID, err = primitive.ObjectIDFromHex(filter.MemberID); err != nil {
+			return nil, db.ErrNotFound
+		}
+	}
+	tableIDs := map[primitive.ObjectID]bool{}
+	for _, id := range filter.TableIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, db.ErrNotFound
+		}
+		tableIDs[objectID] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reservations := []types.Reservation{}
+	for _, reservation := range r.reservations {
+		switch {
+		case reservation.DelFlg,
+			filter.TableID != "" && reservation.TableID != tableID,
+			len(filter.TableIDs) > 0 && !tableIDs[reservation.TableID],
+			filter.MemberID != "" && reservation.MemberID != memberID,
+			!filter.To.IsZero() && !reservation.StartTime.Before(filter.To),
+			!filter.From.IsZero() && !reservation.EndTime.After(filter.From):
+			continue
+		}
+		reservations = append(reservations, reservation)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].StartTime.Before(reservations[j].StartTime)
+	})
+	return reservations, nil
+}
+
+// Insert Reservation in memory, db.ErrOverlap is returned
+// when the table is already reserved for an overlapping period
+func (r *MemoryRepository) Insert(ctx context.Context, reservation types.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reservations[reservation.ID]; ok {
+		return db.ErrDuplicateKey
+	}
+	if r.overlaps(reservation) {
+		return db.ErrOverlap
+	}
+	r.reservations[reservation.ID] = reservation
+	return nil
+}
+
+// Update Reservation by using ID, db.ErrOverlap is returned
+// when the table is already reserved for an overlapping period
+func (r *MemoryRepository) Update(ctx context.Context, reservation types.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.overlaps(reservation) {
+		return db.ErrOverlap
+	}
+	updated, ok := r.reservations[reservation.ID]
+	if !ok {
+		return nil
+	}
+	updated.TableID = reservation.TableID
+	updated.Name = reservation.Name
+	updated.Phone = reservation.Phone
+	updated.PartySize = reservation.PartySize
+	updated.StartTime = reservation.StartTime
+	updated.EndTime = reservation.EndTime
+	updated.Note = reservation.Note
+	updated.UpdateAt = reservation.UpdateAt
+	r.reservations[reservation.ID] = updated
+	return nil
+}
+
+// overlaps return true if another reservation of the same table overlaps the given one,
+// the caller must hold the lock so checking and writing is atomic
+func (r *MemoryRepository) overlaps(reservation types.Reservation) bool {
+	for id, other := range r.reservations {
+		if id != reservation.ID && other.TableID == reservation.TableID && !other.DelFlg &&
+			other.StartTime.Before(reservation.EndTime) && other.EndTime.After(reservation.StartTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delete Reservation by using ID, the reservation is only flagged as deleted
+func (r *MemoryRepository) Delete(ctx context.Context, id string) error {
+	reservationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return db.ErrNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reservation, ok := r.reservations[reservationID]; ok {
+		reservation.DelFlg = true
+		reservation.UpdateAt = time.Now()
+		r.reservations[reservationID] = reservation
+	}
+	return nil
+}
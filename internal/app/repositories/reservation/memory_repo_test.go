@@ -0,0 +1,14 @@
+package reservation
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	reservationServices "booking/internal/app/services/reservation"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.ReservationRepository(t, func(t *testing.T) reservationServices.Repository {
+		return NewMemoryRepository()
+	})
+}
@@ -0,0 +1,18 @@
+//go:build integration
+// +build integration
+
+package reservation
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	reservationServices "booking/internal/app/services/reservation"
+)
+
+func TestMongoRepository(t *testing.T) {
+	database := repotest.Mongo(t)
+	repotest.ReservationRepository(t, func(t *testing.T) reservationServices.Repository {
+		return NewMongoRepository(database)
+	})
+}
@@ -0,0 +1,177 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository is MongoDB implementation of repository
+type MongoRepository struct {
+	db *db.Mongo
+}
+
+func NewMongoRepository(m *db.Mongo) *MongoRepository {
+	return &MongoRepository{
+		db: m,
+	}
+}
+
+func (r *MongoRepository) collection() *mongo.Collection {
+	return r.db.Collection("reservations")
+}
+
+func (r *MongoRepository) locks() *mongo.Collection {
+	return r.db.Collection("reservation_locks")
+}
+
+// FindByID return reservation base on given id
+func (r *MongoRepository) FindByID(ctx context.Context, id string) (*types.Reservation, error) {
+	// convert id string to ObjectId
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+	var reservation *types.Reservation
+	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&reservation)
+	return reservation, db.MongoError(err)
+}
+
+// Find return not deleted reservations matching given filter, ordered by start time
+func (r *MongoRepository) Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error) {
+	query := bson.M{"del_flg": false}
+	table := bson.M{}
+	if filter.TableID != "" {
+		tableID, err := primitive.ObjectIDFromHex(filter.TableID)
+		if err != nil {
+			return nil, db.MongoError(err)
+		}
+		table["$eq"] = tableID
+	}
+	if len(filter.TableIDs) > 0 {
+		tableIDs := make([]primitive.ObjectID, 0, len(filter.TableIDs))
+		for _, id := range filter.TableIDs {
+			tableID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return nil, db.MongoError(err)
+			}
+			tableIDs = append(tableIDs, tableID)
+		}
+		table["$in"] = tableIDs
+	}
+	if len(table) > 0 {
+		query["table_id"] = table
+	}
+	if filter.MemberID != "" {
+		memberID, err := primitive.ObjectIDFromHex(filter.MemberID)
+		if err != nil {
+			return nil, db.MongoError(err)
+		}
+		query["member_id"] = memberID
+	}
+	if !filter.To.IsZero() {
+		query["start_time"] = bson.M{"$lt": filter.To}
+	}
+	if !filter.From.IsZero() {
+		query["end_time"] = bson.M{"$gt": filter.From}
+	}
+
+	opts := options.Find().SetSort(bson.M{"start_time": 1})
+	cursor, err := r.collection().Find(ctx, query, opts)
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+
+	reservations := []types.Reservation{}
+	err = cursor.All(ctx, &reservations)
+	return reservations, db.MongoError(err)
+}
+
+// Insert Reservation to DB Mongo, db.ErrOverlap is returned
+// when the table is already reserved for an overlapping period
+func (r *MongoRepository) Insert(ctx context.Context, reservation types.Reservation) error {
+	return r.reserve(ctx, reservation, func(sc mongo.SessionContext) error {
+		_, err := r.collection().InsertOne(sc, reservation)
+		return db.MongoError(err)
+	})
+}
+
+// Update Reservation by using ID, db.ErrOverlap is returned
+// when the table is already reserved for an overlapping period
+func (r *MongoRepository) Update(ctx context.Context, reservation types.Reservation) error {
+	updatedReservation := bson.M{"$set": bson.M{
+		"table_id":   reservation.TableID,
+		"name":       reservation.Name,
+		"phone":      reservation.Phone,
+		"party_size": reservation.PartySize,
+		"start_time": reservation.StartTime,
+		"end_time":   reservation.EndTime,
+		"note":       reservation.Note,
+		"update_at":  reservation.UpdateAt,
+	}}
+
+	return r.reserve(ctx, reservation, func(sc mongo.SessionContext) error {
+		_, err := r.collection().UpdateByID(sc, reservation.ID, updatedReservation)
+		return db.MongoError(err)
+	})
+}
+
+// reserve runs write in a transaction once no other reservation of the same table
+// overlaps the given one. The lock document of the table is bumped in the same
+// transaction, so concurrent reservations of a table hit a write conflict and
+// are retried by the driver instead of both passing the overlap check.
+func (r *MongoRepository) reserve(ctx context.Context, reservation types.Reservation, write func(sc mongo.SessionContext) error) error {
+	session, err := r.db.Client().StartSession()
+	if err != nil {
+		return db.MongoError(err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		lock := bson.M{"$inc": bson.M{"seq": 1}}
+		if _, err := r.locks().UpdateByID(sc, reservation.TableID, lock, options.Update().SetUpsert(true)); err != nil {
+			return nil, db.MongoError(err)
+		}
+
+		overlap := bson.M{
+			"_id":        bson.M{"$ne": reservation.ID},
+			"table_id":   reservation.TableID,
+			"del_flg":    false,
+			"start_time": bson.M{"$lt": reservation.EndTime},
+			"end_time":   bson.M{"$gt": reservation.StartTime},
+		}
+		n, err := r.collection().CountDocuments(sc, overlap, options.Count().SetLimit(1))
+		if err != nil {
+			return nil, db.MongoError(err)
+		}
+		if n > 0 {
+			return nil, db.ErrOverlap
+		}
+
+		return nil, write(sc)
+	})
+	return db.MongoError(err)
+}
+
+// Delete Reservation by using ID, the reservation is only flagged as deleted
+func (r *MongoRepository) Delete(ctx context.Context, id string) error {
+	reservationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return db.MongoError(err)
+	}
+
+	deletedReservation := bson.M{"$set": bson.M{
+		"del_flg":   true,
+		"update_at": time.Now(),
+	}}
+
+	_, err = r.collection().UpdateByID(ctx, reservationID, deletedReservation)
+	return db.MongoError(err)
+}
@@ -0,0 +1,211 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const reservationColumns = "id, table_id, member_id, name, phone, party_size, start_time, end_time, note, del_flg, create_at, update_at"
+
+// SQLRepository is database/sql implementation of repository
+type SQLRepository struct {
+	db *db.SQL
+}
+
+func NewSQLRepository(s *db.SQL) *SQLRepository {
+	return &SQLRepository{
+		db: s,
+	}
+}
+
+// CreateTables create the tables of reservations if they do not exist
+func (r *SQLRepository) CreateTables(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, r.db.Schema(`CREATE TABLE IF NOT EXISTS reservations (
+		id CHAR(24) PRIMARY KEY,
+		table_id CHAR(24) NOT NULL,
+		member_id CHAR(24) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		phone VARCHAR(32) NOT NULL,
+		party_size INTEGER NOT NULL,
+		start_time {time} NOT NULL,
+		end_time {time} NOT NULL,
+		note {text} NOT NULL,
+		del_flg BOOLEAN NOT NULL DEFAULT FALSE,
+		create_at {time} NULL,
+		update_at {time} NULL
+	)`))
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if err := r.db.CreateIndex(ctx, "reservations_table_id_start_time", "reservations", "table_id, start_time"); err != nil {
+		return r.db.Error(err)
+	}
+	if err := r.db.CreateIndex(ctx, "reservations_member_id", "reservations", "member_id"); err != nil {
+		return r.db.Error(err)
+	}
+	_, err = r.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS reservation_locks (
+		table_id CHAR(24) PRIMARY KEY,
+		seq BIGINT NOT NULL
+	)`)
+	return r.db.Error(err)
+}
+
+// FindByID return reservation base on given id
+func (r *SQLRepository) FindByID(ctx context.Context, id string) (*types.Reservation, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, r.db.Error(err)
+	}
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+reservationColumns+" FROM reservations WHERE id = ?"), id)
+	reservation, err := scanReservation(row)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	return reservation, nil
+}
+
+// Find return not deleted reservations matching given filter, ordered by start time
+func (r *SQLRepository) Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error) {
+	where := []string{"del_flg = ?"}
+	args := []interface{}{false}
+	if filter.TableID != "" {
+		if _, err := primitive.ObjectIDFromHex(filter.TableID); err != nil {
+			return nil, r.db.Error(err)
+		}
+		where = append(where, "table_id = ?")
+		args = append(args, filter.TableID)
+	}
+	if len(filter.TableIDs) > 0 {
+		placeholders := make([]string, 0, len(filter.TableIDs))
+		for _, id := range filter.TableIDs {
+			if _, err := primitive.ObjectIDFromHex(id); err != nil {
+				return nil, r.db.Error(err)
+			}
+			placeholders = append(placeholders, "?")
+			args = append(args, id)
+		}
+		where = append(where, "table_id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.MemberID != "" {
+		if _, err := primitive.ObjectIDFromHex(filter.MemberID); err != nil {
+			return nil, r.db.Error(err)
+		}
+		where = append(where, "member_id = ?")
+		args = append(args, filter.MemberID)
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "start_time < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if !filter.From.IsZero() {
+		where = append(where, "end_time > ?")
+		args = append(args, filter.From.UTC())
+	}
+
+	query := "SELECT " + reservationColumns + " FROM reservations WHERE " + strings.Join(where, " AND ") + " ORDER BY start_time"
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	reservations := []types.Reservation{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, r.db.Error(err)
+		}
+		reservations = append(reservations, *reservation)
+	}
+	return reservations, rows.Err()
+}
+
+// Insert Reservation to SQL database, db.ErrOverlap is returned
+// when the table is already reserved for an overlapping period
+func (r *SQLRepository) Insert(ctx context.Context, reservation types.Reservation) error {
+	return r.reserve(ctx, reservation, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, r.db.Rebind("INSERT INTO reservations ("+reservationColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+			reservation.ID.Hex(), reservation.TableID.Hex(), reservation.MemberID.Hex(), reservation.Name, reservation.Phone,
+			reservation.PartySize, reservation.StartTime.UTC(), reservation.EndTime.UTC(), reservation.Note, reservation.DelFlg,
+			db.NullTime(reservation.CreateAt.UTC()), db.NullTime(reservation.UpdateAt.UTC()))
+		return r.db.Error(err)
+	})
+}
+
+// Update Reservation by using ID, db.ErrOverlap is returned
+// when the table is already reserved for an overlapping period
+func (r *SQLRepository) Update(ctx context.Context, reservation types.Reservation) error {
+	return r.reserve(ctx, reservation, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, r.db.Rebind("UPDATE reservations SET table_id = ?, name = ?, phone = ?, party_size = ?, start_time = ?, end_time = ?, note = ?, update_at = ? WHERE id = ?"),
+			reservation.TableID.Hex(), reservation.Name, reservation.Phone, reservation.PartySize, reservation.StartTime.UTC(),
+			reservation.EndTime.UTC(), reservation.Note, db.NullTime(reservation.UpdateAt.UTC()), reservation.ID.Hex())
+		return r.db.Error(err)
+	})
+}
+
+// reserve runs write in a transaction once no other reservation of the same table
+// overlaps the given one. The lock row of the table is bumped first in the same
+// transaction, so concurrent reservations of a table wait for each other instead
+// of both passing the overlap check.
+func (r *SQLRepository) reserve(ctx context.Context, reservation types.Reservation, write func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback()
+
+	tableID := reservation.TableID.Hex()
+	if _, err := tx.ExecContext(ctx, r.db.Rebind(r.db.InsertIgnore("INSERT INTO reservation_locks (table_id, seq) VALUES (?, 0)")), tableID); err != nil {
+		return r.db.Error(err)
+	}
+	if _, err := tx.ExecContext(ctx, r.db.Rebind("UPDATE reservation_locks SET seq = seq + 1 WHERE table_id = ?"), tableID); err != nil {
+		return r.db.Error(err)
+	}
+
+	var n int
+	err = tx.QueryRowContext(ctx, r.db.Rebind("SELECT COUNT(*) FROM reservations WHERE id <> ? AND table_id = ? AND del_flg = ? AND start_time < ? AND end_time > ?"),
+		reservation.ID.Hex(), tableID, false, reservation.EndTime.UTC(), reservation.StartTime.UTC()).Scan(&n)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if n > 0 {
+		return db.ErrOverlap
+	}
+
+	if err := write(tx); err != nil {
+		return r.db.Error(err)
+	}
+	return tx.Commit()
+}
+
+// Delete Reservation by using ID, the reservation is only flagged as deleted
+func (r *SQLRepository) Delete(ctx context.Context, id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return r.db.Error(err)
+	}
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE reservations SET del_flg = ?, update_at = ? WHERE id = ?"), true, time.Now().UTC(), id)
+	return r.db.Error(err)
+}
+
+func scanReservation(s db.Scanner) (*types.Reservation, error) {
+	var reservation types.Reservation
+	var id, tableID, memberID string
+	var startTime, endTime, createAt, updateAt db.Time
+	err := s.Scan(&id, &tableID, &memberID, &reservation.Name, &reservation.Phone, &reservation.PartySize,
+		&startTime, &endTime, &reservation.Note, &reservation.DelFlg, &createAt, &updateAt)
+	if err != nil {
+		return nil, err
+	}
+	reservation.ID, _ = primitive.ObjectIDFromHex(id)
+	reservation.TableID, _ = primitive.ObjectIDFromHex(tableID)
+	reservation.MemberID, _ = primitive.ObjectIDFromHex(memberID)
+	reservation.StartTime, reservation.EndTime = time.Time(startTime), time.Time(endTime)
+	reservation.CreateAt, reservation.UpdateAt = time.Time(createAt), time.Time(updateAt)
+	return &reservation, nil
+}
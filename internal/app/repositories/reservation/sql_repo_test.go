@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	reservationServices "booking/internal/app/services/reservation"
+)
+
+func TestSQLRepository(t *testing.T) {
+	repotest.ReservationRepository(t, func(t *testing.T) reservationServices.Repository {
+		repo := NewSQLRepository(repotest.SQLite(t))
+		if err := repo.CreateTables(context.Background()); err != nil {
+			t.Fatalf("failed to create tables, err: %v", err)
+		}
+		return repo
+	})
+}
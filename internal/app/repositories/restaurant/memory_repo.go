@@ -0,0 +1,103 @@
+package restaurant
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is in-memory implementation of repository
+type MemoryRepository struct {
+	mu          sync.RWMutex
+	restaurants map[primitive.ObjectID]types.Restaurant
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		restaurants: map[primitive.ObjectID]types.Restaurant{},
+	}
+}
+
+// FindByID return restaurant base on given id
+func (r *MemoryRepository) FindByID(ctx context.Context, id string) (*types.Restaurant, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.ErrNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	restaurant, ok := r.restaurants[objectID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &restaurant, nil
+}
+
+// FindAll return all restaurants which are not deleted, ordered by name
+func (r *MemoryRepository) FindAll(ctx context.Context) ([]types.Restaurant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	restaurants := []types.Restaurant{}
+	for _, restaurant := range r.restaurants {
+		if !restaurant.DelFlg {
+			restaurants = append(restaurants, restaurant)
+		}
+	}
+	sort.Slice(restaurants, func(i, j int) bool {
+		return restaurants[i].Name < restaurants[j].Name
+	})
+	return restaurants, nil
+}
+
+// Insert Restaurant in memory
+func (r *MemoryRepository) Insert(ctx context.Context, restaurant types.Restaurant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.restaurants[restaurant.ID]; ok {
+		return db.ErrDuplicateKey
+	}
+	r.restaurants[restaurant.ID] = restaurant
+	return nil
+}
+
+// Update Restaurant by using ID
+func (r *MemoryRepository) UpdateRestaurantByID(ctx context.Context, restaurantReq types.UpdateRestaurantRequest) error {
+	restaurantId, err := primitive.ObjectIDFromHex(restaurantReq.ID)
+	if err != nil {
+		return db.ErrNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if restaurant, ok := r.restaurants[restaurantId]; ok {
+		restaurant.Name = restaurantReq.Name
+		restaurant.Address = restaurantReq.Address
+		restaurant.UpdateAt = time.Now()
+		r.restaurants[restaurantId] = restaurant
+	}
+	return nil
+}
+
+// Delete Restaurant by using ID
+func (r *MemoryRepository) DeleteRestaurant(ctx context.Context, restaurantReq types.DeleteRestaurantRequest) error {
+	restaurantId, err := primitive.ObjectIDFromHex(restaurantReq.ID)
+	if err != nil {
+		return db.ErrNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if restaurant, ok := r.restaurants[restaurantId]; ok {
+		restaurant.DelFlg = restaurantReq.DelFlg
+		restaurant.UpdateAt = time.Now()
+		r.restaurants[restaurantId] = restaurant
+	}
+	return nil
+}
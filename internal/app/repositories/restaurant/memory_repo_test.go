@@ -0,0 +1,14 @@
+package restaurant
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	restaurantServices "booking/internal/app/services/restaurant"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.RestaurantRepository(t, func(t *testing.T) restaurantServices.Repository {
+		return NewMemoryRepository()
+	})
+}
@@ -0,0 +1,18 @@
+//go:build integration
+// +build integration
+
+package restaurant
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	restaurantServices "booking/internal/app/services/restaurant"
+)
+
+func TestMongoRepository(t *testing.T) {
+	database := repotest.Mongo(t)
+	repotest.RestaurantRepository(t, func(t *testing.T) restaurantServices.Repository {
+		return NewMongoRepository(database)
+	})
+}
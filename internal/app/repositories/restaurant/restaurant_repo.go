@@ -0,0 +1,93 @@
+package restaurant
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRepository is MongoDB implementation of repository
+type MongoRepository struct {
+	db *db.Mongo
+}
+
+func NewMongoRepository(m *db.Mongo) *MongoRepository {
+	return &MongoRepository{
+		db: m,
+	}
+}
+
+func (r *MongoRepository) collection() *mongo.Collection {
+	return r.db.Collection("restaurants")
+}
+
+// FindByID return restaurant base on given id
+func (r *MongoRepository) FindByID(ctx context.Context, id string) (*types.Restaurant, error) {
+	// convert id string to ObjectId
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+	var restaurant *types.Restaurant
+	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&restaurant)
+	return restaurant, db.MongoError(err)
+}
+
+// FindAll return all restaurants which are not deleted, ordered by name
+func (r *MongoRepository) FindAll(ctx context.Context) ([]types.Restaurant, error) {
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.collection().Find(ctx, bson.M{"del_flg": false}, opts)
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+
+	restaurants := []types.Restaurant{}
+	err = cursor.All(ctx, &restaurants)
+	return restaurants, db.MongoError(err)
+}
+
+// Insert Restaurant to DB Mongo
+func (r *MongoRepository) Insert(ctx context.Context, restaurant types.Restaurant) error {
+	_, err := r.collection().InsertOne(ctx, restaurant)
+	return db.MongoError(err)
+}
+
+// Update Restaurant by using ID
+func (r *MongoRepository) UpdateRestaurantByID(ctx context.Context, restaurantReq types.UpdateRestaurantRequest) error {
+	restaurantId, err := primitive.ObjectIDFromHex(restaurantReq.ID)
+	if err != nil {
+		return db.MongoError(err)
+	}
+
+	updatedRestaurant := bson.M{"$set": bson.M{
+		"name":      restaurantReq.Name,
+		"address":   restaurantReq.Address,
+		"update_at": time.Now(),
+	}}
+
+	_, err = r.collection().UpdateByID(ctx, restaurantId, updatedRestaurant)
+	return db.MongoError(err)
+}
+
+// Delete Restaurant by using ID
+func (r *MongoRepository) DeleteRestaurant(ctx context.Context, restaurantReq types.DeleteRestaurantRequest) error {
+	restaurantId, err := primitive.ObjectIDFromHex(restaurantReq.ID)
+	if err != nil {
+		return db.MongoError(err)
+	}
+
+	updatedRestaurant := bson.M{"$set": bson.M{
+		"del_flg":   restaurantReq.DelFlg,
+		"update_at": time.Now(),
+	}}
+
+	_, err = r.collection().UpdateByID(ctx, restaurantId, updatedRestaurant)
+	return db.MongoError(err)
+}
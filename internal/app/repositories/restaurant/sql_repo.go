@@ -0,0 +1,109 @@
+package restaurant
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const restaurantColumns = "id, name, address, del_flg, create_at, update_at"
+
+// SQLRepository is database/sql implementation of repository
+type SQLRepository struct {
+	db *db.SQL
+}
+
+func NewSQLRepository(s *db.SQL) *SQLRepository {
+	return &SQLRepository{
+		db: s,
+	}
+}
+
+// CreateTables create the table of restaurants if it does not exist
+func (r *SQLRepository) CreateTables(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, r.db.Schema(`CREATE TABLE IF NOT EXISTS restaurants (
+		id CHAR(24) PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		address VARCHAR(255) NOT NULL,
+		del_flg BOOLEAN NOT NULL DEFAULT FALSE,
+		create_at {time} NULL,
+		update_at {time} NULL
+	)`))
+	return r.db.Error(err)
+}
+
+// FindByID return restaurant base on given id
+func (r *SQLRepository) FindByID(ctx context.Context, id string) (*types.Restaurant, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, r.db.Error(err)
+	}
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+restaurantColumns+" FROM restaurants WHERE id = ?"), id)
+	restaurant, err := scanRestaurant(row)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	return restaurant, nil
+}
+
+// FindAll return all restaurants which are not deleted, ordered by name
+func (r *SQLRepository) FindAll(ctx context.Context) ([]types.Restaurant, error) {
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind("SELECT "+restaurantColumns+" FROM restaurants WHERE del_flg = ? ORDER BY name"), false)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	restaurants := []types.Restaurant{}
+	for rows.Next() {
+		restaurant, err := scanRestaurant(rows)
+		if err != nil {
+			return nil, r.db.Error(err)
+		}
+		restaurants = append(restaurants, *restaurant)
+	}
+	return restaurants, rows.Err()
+}
+
+// Insert Restaurant to SQL database
+func (r *SQLRepository) Insert(ctx context.Context, restaurant types.Restaurant) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("INSERT INTO restaurants ("+restaurantColumns+") VALUES (?, ?, ?, ?, ?, ?)"),
+		restaurant.ID.Hex(), restaurant.Name, restaurant.Address, restaurant.DelFlg,
+		db.NullTime(restaurant.CreateAt.UTC()), db.NullTime(restaurant.UpdateAt.UTC()))
+	return r.db.Error(err)
+}
+
+// Update Restaurant by using ID
+func (r *SQLRepository) UpdateRestaurantByID(ctx context.Context, restaurantReq types.UpdateRestaurantRequest) error {
+	if _, err := primitive.ObjectIDFromHex(restaurantReq.ID); err != nil {
+		return r.db.Error(err)
+	}
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE restaurants SET name = ?, address = ?, update_at = ? WHERE id = ?"),
+		restaurantReq.Name, restaurantReq.Address, time.Now().UTC(), restaurantReq.ID)
+	return r.db.Error(err)
+}
+
+// Delete Restaurant by using ID
+func (r *SQLRepository) DeleteRestaurant(ctx context.Context, restaurantReq types.DeleteRestaurantRequest) error {
+	if _, err := primitive.ObjectIDFromHex(restaurantReq.ID); err != nil {
+		return r.db.Error(err)
+	}
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE restaurants SET del_flg = ?, update_at = ? WHERE id = ?"),
+		restaurantReq.DelFlg, time.Now().UTC(), restaurantReq.ID)
+	return r.db.Error(err)
+}
+
+func scanRestaurant(s db.Scanner) (*types.Restaurant, error) {
+	var restaurant types.Restaurant
+	var id string
+	var createAt, updateAt db.Time
+	if err := s.Scan(&id, &restaurant.Name, &restaurant.Address, &restaurant.DelFlg, &createAt, &updateAt); err != nil {
+		return nil, err
+	}
+	restaurant.ID, _ = primitive.ObjectIDFromHex(id)
+	restaurant.CreateAt, restaurant.UpdateAt = time.Time(createAt), time.Time(updateAt)
+	return &restaurant, nil
+}
@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package restaurant
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	restaurantServices "booking/internal/app/services/restaurant"
+)
+
+func TestSQLRepository(t *testing.T) {
+	repotest.RestaurantRepository(t, func(t *testing.T) restaurantServices.Repository {
+		repo := NewSQLRepository(repotest.SQLite(t))
+		if err := repo.CreateTables(context.Background()); err != nil {
+			t.Fatalf("failed to create tables, err: %v", err)
+		}
+		return repo
+	})
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is in-memory implementation of repository
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	sessions map[primitive.ObjectID]types.Session
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		sessions: map[primitive.ObjectID]types.Session{},
+	}
+}
+
+// FindByID return session base on given id
+func (r *MemoryRepository) FindByID(ctx context.Context, id string) (*types.Session, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.ErrNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	session, ok := r.sessions[objectID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &session, nil
+}
+
+// Insert Session in memory
+func (r *MemoryRepository) Insert(ctx context.Context, session types.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sessions[session.ID]; ok {
+		return db.ErrDuplicateKey
+	}
+	r.sessions[session.ID] = session
+	return nil
+}
+
+// Rotate replace the refresh token hash of an active session if it still is oldHash,
+// false is returned when the session was revoked or rotated meanwhile
+func (r *MemoryRepository) Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok || session.Revoked || session.RefreshTokenHash != oldHash {
+		return false, nil
+	}
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = expiresAt
+	session.UpdateAt = time.Now()
+	r.sessions[id] = session
+	return true, nil
+}
+
+// Revoke Session by using ID
+func (r *MemoryRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[id]; ok {
+		session.Revoked = true
+		session.UpdateAt = time.Now()
+		r.sessions[id] = session
+	}
+	return nil
+}
+
+// RevokeByMember revoke every session of a member
+func (r *MemoryRepository) RevokeByMember(ctx context.Context, memberID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, session := range r.sessions {
+		if session.MemberID == memberID && !session.Revoked {
+			session.Revoked = true
+			session.UpdateAt = time.Now()
+			r.sessions[id] = session
+		}
+	}
+	return nil
+}
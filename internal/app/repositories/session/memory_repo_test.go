@@ -0,0 +1,14 @@
+package session
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	sessionServices "booking/internal/app/services/session"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.SessionRepository(t, func(t *testing.T) sessionServices.Repository {
+		return NewMemoryRepository()
+	})
+}
@@ -0,0 +1,18 @@
+//go:build integration
+// +build integration
+
+package session
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	sessionServices "booking/internal/app/services/session"
+)
+
+func TestMongoRepository(t *testing.T) {
+	database := repotest.Mongo(t)
+	repotest.SessionRepository(t, func(t *testing.T) sessionServices.Repository {
+		return NewMongoRepository(database)
+	})
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoRepository is MongoDB implementation of repository
+type MongoRepository struct {
+	db *db.Mongo
+}
+
+func NewMongoRepository(m *db.Mongo) *MongoRepository {
+	return &MongoRepository{
+		db: m,
+	}
+}
+
+func (r *MongoRepository) collection() *mongo.Collection {
+	return r.db.Collection("sessions")
+}
+
+// FindByID return session base on given id
+func (r *MongoRepository) FindByID(ctx context.Context, id string) (*types.Session, error) {
+	// convert id string to ObjectId
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+	var session *types.Session
+	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&session)
+	return session, db.MongoError(err)
+}
+
+// Insert Session to DB Mongo
+func (r *MongoRepository) Insert(ctx context.Context, session types.Session) error {
+	_, err := r.collection().InsertOne(ctx, session)
+	return db.MongoError(err)
+}
+
+// Rotate replace the refresh token hash of an active session if it still is oldHash,
+// false is returned when the session was revoked or rotated meanwhile
+func (r *MongoRepository) Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":                id,
+		"refresh_token_hash": oldHash,
+		"revoked":            false,
+	}
+	rotatedSession := bson.M{"$set": bson.M{
+		"refresh_token_hash": newHash,
+		"expires_at":         expiresAt,
+		"update_at":          time.Now(),
+	}}
+
+	res, err := r.collection().UpdateOne(ctx, filter, rotatedSession)
+	if err != nil {
+		return false, db.MongoError(err)
+	}
+	return res.MatchedCount == 1, nil
+}
+
+// Revoke Session by using ID
+func (r *MongoRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	revokedSession := bson.M{"$set": bson.M{
+		"revoked":   true,
+		"update_at": time.Now(),
+	}}
+
+	_, err := r.collection().UpdateByID(ctx, id, revokedSession)
+	return db.MongoError(err)
+}
+
+// RevokeByMember revoke every session of a member
+func (r *MongoRepository) RevokeByMember(ctx context.Context, memberID primitive.ObjectID) error {
+	revokedSession := bson.M{"$set": bson.M{
+		"revoked":   true,
+		"update_at": time.Now(),
+	}}
+
+	_, err := r.collection().UpdateMany(ctx, bson.M{"member_id": memberID, "revoked": false}, revokedSession)
+	return db.MongoError(err)
+}
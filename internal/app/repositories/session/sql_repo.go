@@ -0,0 +1,94 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const sessionColumns = "id, member_id, refresh_token_hash, expires_at, revoked, create_at, update_at"
+
+// SQLRepository is database/sql implementation of repository
+type SQLRepository struct {
+	db *db.SQL
+}
+
+func NewSQLRepository(s *db.SQL) *SQLRepository {
+	return &SQLRepository{
+		db: s,
+	}
+}
+
+// CreateTables create the table of sessions if it does not exist
+func (r *SQLRepository) CreateTables(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, r.db.Schema(`CREATE TABLE IF NOT EXISTS sessions (
+		id CHAR(24) PRIMARY KEY,
+		member_id CHAR(24) NOT NULL,
+		refresh_token_hash VARCHAR(255) NOT NULL,
+		expires_at {time} NULL,
+		revoked BOOLEAN NOT NULL DEFAULT FALSE,
+		create_at {time} NULL,
+		update_at {time} NULL
+	)`))
+	if err != nil {
+		return r.db.Error(err)
+	}
+	return r.db.CreateIndex(ctx, "sessions_member_id", "sessions", "member_id")
+}
+
+// FindByID return session base on given id
+func (r *SQLRepository) FindByID(ctx context.Context, id string) (*types.Session, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	var session types.Session
+	var sessionID, memberID string
+	var expiresAt, createAt, updateAt db.Time
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+sessionColumns+" FROM sessions WHERE id = ?"), id).
+		Scan(&sessionID, &memberID, &session.RefreshTokenHash, &expiresAt, &session.Revoked, &createAt, &updateAt)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	session.ID, _ = primitive.ObjectIDFromHex(sessionID)
+	session.MemberID, _ = primitive.ObjectIDFromHex(memberID)
+	session.ExpiresAt, session.CreateAt, session.UpdateAt = time.Time(expiresAt), time.Time(createAt), time.Time(updateAt)
+	return &session, nil
+}
+
+// Insert Session to SQL database
+func (r *SQLRepository) Insert(ctx context.Context, session types.Session) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("INSERT INTO sessions ("+sessionColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)"),
+		session.ID.Hex(), session.MemberID.Hex(), session.RefreshTokenHash, db.NullTime(session.ExpiresAt.UTC()),
+		session.Revoked, db.NullTime(session.CreateAt.UTC()), db.NullTime(session.UpdateAt.UTC()))
+	return r.db.Error(err)
+}
+
+// Rotate replace the refresh token hash of an active session if it still is oldHash,
+// false is returned when the session was revoked or rotated meanwhile
+func (r *SQLRepository) Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE sessions SET refresh_token_hash = ?, expires_at = ?, update_at = ? WHERE id = ? AND revoked = ? AND refresh_token_hash = ?"),
+		newHash, expiresAt.UTC(), time.Now().UTC(), id.Hex(), false, oldHash)
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+	n, err := res.RowsAffected()
+	return n == 1, r.db.Error(err)
+}
+
+// Revoke Session by using ID
+func (r *SQLRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE sessions SET revoked = ?, update_at = ? WHERE id = ?"), true, time.Now().UTC(), id.Hex())
+	return r.db.Error(err)
+}
+
+// RevokeByMember revoke every session of a member
+func (r *SQLRepository) RevokeByMember(ctx context.Context, memberID primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE sessions SET revoked = ?, update_at = ? WHERE member_id = ? AND revoked = ?"),
+		true, time.Now().UTC(), memberID.Hex(), false)
+	return r.db.Error(err)
+}
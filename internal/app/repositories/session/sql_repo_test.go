@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package session
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	sessionServices "booking/internal/app/services/session"
+)
+
+func TestSQLRepository(t *testing.T) {
+	repotest.SessionRepository(t, func(t *testing.T) sessionServices.Repository {
+		repo := NewSQLRepository(repotest.SQLite(t))
+		if err := repo.CreateTables(context.Background()); err != nil {
+			t.Fatalf("failed to create tables, err: %v", err)
+		}
+		return repo
+	})
+}
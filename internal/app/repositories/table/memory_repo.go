@@ -0,0 +1,104 @@
+package table
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is in-memory implementation of repository
+type MemoryRepository struct {
+	mu     sync.RWMutex
+	tables map[primitive.ObjectID]types.Table
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		tables: map[primitive.ObjectID]types.Table{},
+	}
+}
+
+// FindByID return table base on given id
+func (r *MemoryRepository) FindByID(ctx context.Context, id string) (*types.Table, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, db.ErrNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	table, ok := r.tables[objectID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &table, nil
+}
+
+// Insert Table in memory
+func (r *MemoryRepository) Insert(ctx context.Context, table types.Table) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tables[table.ID]; ok {
+		return db.ErrDuplicateKey
+	}
+	r.tables[table.ID] = table
+	return nil
+}
+
+// Update Table by using ID
+func (r *MemoryRepository) UpdateTableByID(ctx context.Context, tableReq types.UpdateTableRequest) error {
+	tableId, err := primitive.ObjectIDFromHex(tableReq.ID)
+	if err != nil {
+		return db.ErrNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if table, ok := r.tables[tableId]; ok {
+		table.Status = tableReq.Status
+		r.tables[tableId] = table
+	}
+	return nil
+}
+
+// Delete Table by using ID
+func (r *MemoryRepository) DeleteTable(ctx context.Context, tableReq types.DeleteTableRequest) error {
+	tableId, err := primitive.ObjectIDFromHex(tableReq.ID)
+	if err != nil {
+		return db.ErrNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if table, ok := r.tables[tableId]; ok {
+		table.DelFlg = tableReq.DelFlg
+		r.tables[tableId] = table
+	}
+	return nil
+}
+
+// FindByRestaurant return the tables of a restaurant which are not deleted, ordered by creation
+func (r *MemoryRepository) FindByRestaurant(ctx context.Context, restaurantID string) ([]types.Table, error) {
+	objectID, err := primitive.ObjectIDFromHex(restaurantID)
+	if err != nil {
+		return nil, db.ErrNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tables := []types.Table{}
+	for _, table := range r.tables {
+		if table.RestaurantID == objectID && !table.DelFlg {
+			tables = append(tables, table)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].ID.Hex() < tables[j].ID.Hex()
+	})
+	return tables, nil
+}
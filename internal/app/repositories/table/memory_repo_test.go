@@ -0,0 +1,14 @@
+package table
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	tableServices "booking/internal/app/services/table"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.TableRepository(t, func(t *testing.T) tableServices.Repository {
+		return NewMemoryRepository()
+	})
+}
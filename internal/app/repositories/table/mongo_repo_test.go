@@ -0,0 +1,18 @@
+//go:build integration
+// +build integration
+
+package table
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	tableServices "booking/internal/app/services/table"
+)
+
+func TestMongoRepository(t *testing.T) {
+	database := repotest.Mongo(t)
+	repotest.TableRepository(t, func(t *testing.T) tableServices.Repository {
+		return NewMongoRepository(database)
+	})
+}
@@ -0,0 +1,117 @@
+package table
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const tableColumns = "id, restaurant_id, status, type, slots, del_flg, create_at, update_at"
+
+// SQLRepository is database/sql implementation of repository
+type SQLRepository struct {
+	db *db.SQL
+}
+
+func NewSQLRepository(s *db.SQL) *SQLRepository {
+	return &SQLRepository{
+		db: s,
+	}
+}
+
+// CreateTables create the table of tables if it does not exist,
+// it is named restaurant_tables as TABLES is a keyword of MySQL
+func (r *SQLRepository) CreateTables(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, r.db.Schema(`CREATE TABLE IF NOT EXISTS restaurant_tables (
+		id CHAR(24) PRIMARY KEY,
+		restaurant_id CHAR(24) NOT NULL,
+		status VARCHAR(32) NOT NULL,
+		type VARCHAR(32) NOT NULL,
+		slots INTEGER NOT NULL,
+		del_flg BOOLEAN NOT NULL DEFAULT FALSE,
+		create_at {time} NULL,
+		update_at {time} NULL
+	)`))
+	if err != nil {
+		return r.db.Error(err)
+	}
+	return r.db.CreateIndex(ctx, "restaurant_tables_restaurant_id", "restaurant_tables", "restaurant_id")
+}
+
+// FindByID return table base on given id
+func (r *SQLRepository) FindByID(ctx context.Context, id string) (*types.Table, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, r.db.Error(err)
+	}
+	row := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT "+tableColumns+" FROM restaurant_tables WHERE id = ?"), id)
+	table, err := scanTable(row)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	return table, nil
+}
+
+// Insert Table to SQL database
+func (r *SQLRepository) Insert(ctx context.Context, table types.Table) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("INSERT INTO restaurant_tables ("+tableColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)"),
+		table.ID.Hex(), table.RestaurantID.Hex(), table.Status, table.Type, table.Slots, table.DelFlg,
+		db.NullTime(table.CreateAt.UTC()), db.NullTime(table.UpdateAt.UTC()))
+	return r.db.Error(err)
+}
+
+// Update Table by using ID
+func (r *SQLRepository) UpdateTableByID(ctx context.Context, tableReq types.UpdateTableRequest) error {
+	if _, err := primitive.ObjectIDFromHex(tableReq.ID); err != nil {
+		return r.db.Error(err)
+	}
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE restaurant_tables SET status = ? WHERE id = ?"), tableReq.Status, tableReq.ID)
+	return r.db.Error(err)
+}
+
+// Delete Table by using ID
+func (r *SQLRepository) DeleteTable(ctx context.Context, tableReq types.DeleteTableRequest) error {
+	if _, err := primitive.ObjectIDFromHex(tableReq.ID); err != nil {
+		return r.db.Error(err)
+	}
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE restaurant_tables SET del_flg = ? WHERE id = ?"), tableReq.DelFlg, tableReq.ID)
+	return r.db.Error(err)
+}
+
+// FindByRestaurant return the tables of a restaurant which are not deleted, ordered by creation
+func (r *SQLRepository) FindByRestaurant(ctx context.Context, restaurantID string) ([]types.Table, error) {
+	if _, err := primitive.ObjectIDFromHex(restaurantID); err != nil {
+		return nil, r.db.Error(err)
+	}
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind("SELECT "+tableColumns+" FROM restaurant_tables WHERE restaurant_id = ? AND del_flg = ? ORDER BY id"), restaurantID, false)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	tables := []types.Table{}
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, r.db.Error(err)
+		}
+		tables = append(tables, *table)
+	}
+	return tables, rows.Err()
+}
+
+func scanTable(s db.Scanner) (*types.Table, error) {
+	var table types.Table
+	var id, restaurantID string
+	var createAt, updateAt db.Time
+	if err := s.Scan(&id, &restaurantID, &table.Status, &table.Type, &table.Slots, &table.DelFlg, &createAt, &updateAt); err != nil {
+		return nil, err
+	}
+	table.ID, _ = primitive.ObjectIDFromHex(id)
+	table.RestaurantID, _ = primitive.ObjectIDFromHex(restaurantID)
+	table.CreateAt, table.UpdateAt = time.Time(createAt), time.Time(updateAt)
+	return &table, nil
+}
@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package table
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	tableServices "booking/internal/app/services/table"
+)
+
+func TestSQLRepository(t *testing.T) {
+	repotest.TableRepository(t, func(t *testing.T) tableServices.Repository {
+		repo := NewSQLRepository(repotest.SQLite(t))
+		if err := repo.CreateTables(context.Background()); err != nil {
+			t.Fatalf("failed to create tables, err: %v", err)
+		}
+		return repo
+	})
+}
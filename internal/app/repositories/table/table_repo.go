@@ -3,26 +3,27 @@ package table
 import (
 	"context"
 
+	"booking/internal/app/db"
 	"booking/internal/app/types"
 
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // MongoRepository is MongoDB implementation of repository
 type MongoRepository struct {
-	client *mongo.Client
+	db *db.Mongo
 }
 
-func NewMongoRepository(c *mongo.Client) *MongoRepository {
+func NewMongoRepository(m *db.Mongo) *MongoRepository {
 	return &MongoRepository{
-		client: c,
+		db: m,
 	}
 }
 
 func (r *MongoRepository) collection() *mongo.Collection {
-	return r.client.Database("booking").Collection("tables")
+	return r.db.Collection("tables")
 }
 
 // FindByID return member base on given id
@@ -31,24 +32,24 @@ func (r *MongoRepository) FindByID(ctx context.Context, id string) (*types.Table
 	objectID, err := primitive.ObjectIDFromHex(id)
 
 	if err != nil {
-		return nil, err
+		return nil, db.MongoError(err)
 	}
 	var table *types.Table
 	err = r.collection().FindOne(ctx, bson.M{"_id": objectID}).Decode(&table)
-	return table, err
+	return table, db.MongoError(err)
 }
 
 // Insert Member to DB Mongo
 func (r *MongoRepository) Insert(ctx context.Context, table types.Table) error {
-	_, err := r.collection().InsertOne(context.TODO(), table)
-	return err
+	_, err := r.collection().InsertOne(ctx, table)
+	return db.MongoError(err)
 }
 
 // Update Table by using ID
 func (r *MongoRepository) UpdateTableByID(ctx context.Context, tableReq types.UpdateTableRequest) error {
 	tableId, err := primitive.ObjectIDFromHex(tableReq.ID)
 	if err != nil {
-		return err
+		return db.MongoError(err)
 	}
 
 	updatedTable := bson.M{"$set": bson.M{
@@ -56,14 +57,14 @@ func (r *MongoRepository) UpdateTableByID(ctx context.Context, tableReq types.Up
 	}}
 
 	_, err = r.collection().UpdateByID(ctx, tableId, updatedTable)
-	return err
+	return db.MongoError(err)
 }
 
 // Delete Table by using ID
 func (r *MongoRepository) DeleteTable(ctx context.Context, tableReq types.DeleteTableRequest) error {
 	tableId, err := primitive.ObjectIDFromHex(tableReq.ID)
 	if err != nil {
-		return err
+		return db.MongoError(err)
 	}
 
 	updatedTable := bson.M{"$set": bson.M{
@@ -71,6 +72,22 @@ func (r *MongoRepository) DeleteTable(ctx context.Context, tableReq types.Delete
 	}}
 
 	_, err = r.collection().UpdateByID(ctx, tableId, updatedTable)
-	return err
+	return db.MongoError(err)
 }
 
+// FindByRestaurant return the tables of a restaurant which are not deleted
+func (r *MongoRepository) FindByRestaurant(ctx context.Context, restaurantID string) ([]types.Table, error) {
+	objectID, err := primitive.ObjectIDFromHex(restaurantID)
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+
+	cursor, err := r.collection().Find(ctx, bson.M{"restaurant_id": objectID, "del_flg": false})
+	if err != nil {
+		return nil, db.MongoError(err)
+	}
+
+	tables := []types.Table{}
+	err = cursor.All(ctx, &tables)
+	return tables, db.MongoError(err)
+}
@@ -0,0 +1,56 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+)
+
+// MemoryRepository is in-memory implementation of repository
+type MemoryRepository struct {
+	mu     sync.Mutex
+	tokens []types.MemberToken
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// Insert MemberToken in memory, expired tokens are dropped meanwhile
+func (r *MemoryRepository) Insert(ctx context.Context, token types.MemberToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.ID == token.ID {
+			return db.ErrDuplicateKey
+		}
+	}
+
+	now := time.Now()
+	tokens := r.tokens[:0]
+	for _, t := range r.tokens {
+		if t.ExpiresAt.After(now) {
+			tokens = append(tokens, t)
+		}
+	}
+	r.tokens = append(tokens, token)
+	return nil
+}
+
+// Consume mark the unused and unexpired token with given hash and purpose as used and return it,
+// marking is atomic so a token can only be consumed once
+func (r *MemoryRepository) Consume(ctx context.Context, hash string, purpose types.TokenPurpose) (*types.MemberToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for i, token := range r.tokens {
+		if token.Hash == hash && token.Purpose == purpose && !token.Used && token.ExpiresAt.After(now) {
+			r.tokens[i].Used = true
+			return &token, nil
+		}
+	}
+	return nil, db.ErrNotFound
+}
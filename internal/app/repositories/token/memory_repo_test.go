@@ -0,0 +1,14 @@
+package token
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	memberServices "booking/internal/app/services/member"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.TokenRepository(t, func(t *testing.T) memberServices.TokenRepository {
+		return NewMemoryRepository()
+	})
+}
@@ -0,0 +1,18 @@
+//go:build integration
+// +build integration
+
+package token
+
+import (
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	memberServices "booking/internal/app/services/member"
+)
+
+func TestMongoRepository(t *testing.T) {
+	database := repotest.Mongo(t)
+	repotest.TokenRepository(t, func(t *testing.T) memberServices.TokenRepository {
+		return NewMongoRepository(database)
+	})
+}
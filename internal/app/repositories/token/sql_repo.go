@@ -0,0 +1,80 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SQLRepository is database/sql implementation of repository
+type SQLRepository struct {
+	db *db.SQL
+}
+
+func NewSQLRepository(s *db.SQL) *SQLRepository {
+	return &SQLRepository{
+		db: s,
+	}
+}
+
+// CreateTables create the table of member tokens if it does not exist
+func (r *SQLRepository) CreateTables(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, r.db.Schema(`CREATE TABLE IF NOT EXISTS member_tokens (
+		id CHAR(24) PRIMARY KEY,
+		member_id CHAR(24) NOT NULL,
+		purpose VARCHAR(32) NOT NULL,
+		hash VARCHAR(255) NOT NULL,
+		expires_at {time} NOT NULL,
+		used BOOLEAN NOT NULL DEFAULT FALSE,
+		create_at {time} NULL
+	)`))
+	if err != nil {
+		return r.db.Error(err)
+	}
+	return r.db.CreateIndex(ctx, "member_tokens_hash", "member_tokens", "hash")
+}
+
+// Insert MemberToken to SQL database, expired tokens are deleted meanwhile
+func (r *SQLRepository) Insert(ctx context.Context, token types.MemberToken) error {
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("INSERT INTO member_tokens (id, member_id, purpose, hash, expires_at, used, create_at) VALUES (?, ?, ?, ?, ?, ?, ?)"),
+		token.ID.Hex(), token.MemberID.Hex(), token.Purpose, token.Hash, token.ExpiresAt.UTC(), token.Used, db.NullTime(token.CreateAt.UTC()))
+	if err != nil {
+		return r.db.Error(err)
+	}
+	_, err = r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM member_tokens WHERE expires_at <= ?"), time.Now().UTC())
+	return r.db.Error(err)
+}
+
+// Consume mark the unused and unexpired token with given hash and purpose as used and return it,
+// marking is atomic so a token can only be consumed once
+func (r *SQLRepository) Consume(ctx context.Context, hash string, purpose types.TokenPurpose) (*types.MemberToken, error) {
+	token := types.MemberToken{Hash: hash, Purpose: purpose}
+	var id, memberID string
+	var expiresAt, createAt db.Time
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT id, member_id, expires_at, create_at FROM member_tokens WHERE hash = ? AND purpose = ? AND used = ? AND expires_at > ?"),
+		hash, purpose, false, time.Now().UTC()).Scan(&id, &memberID, &expiresAt, &createAt)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	// only one of concurrent consumers flips the flag
+	res, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE member_tokens SET used = ? WHERE id = ? AND used = ?"), true, id, false)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		if err == nil {
+			err = db.ErrNotFound
+		}
+		return nil, r.db.Error(err)
+	}
+
+	token.ID, _ = primitive.ObjectIDFromHex(id)
+	token.MemberID, _ = primitive.ObjectIDFromHex(memberID)
+	token.ExpiresAt, token.CreateAt = time.Time(expiresAt), time.Time(createAt)
+	return &token, nil
+}
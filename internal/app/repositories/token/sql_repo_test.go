@@ -0,0 +1,22 @@
+//go:build cgo
+// +build cgo
+
+package token
+
+import (
+	"context"
+	"testing"
+
+	"booking/internal/app/repositories/repotest"
+	memberServices "booking/internal/app/services/member"
+)
+
+func TestSQLRepository(t *testing.T) {
+	repotest.TokenRepository(t, func(t *testing.T) memberServices.TokenRepository {
+		repo := NewSQLRepository(repotest.SQLite(t))
+		if err := repo.CreateTables(context.Background()); err != nil {
+			t.Fatalf("failed to create tables, err: %v", err)
+		}
+		return repo
+	})
+}
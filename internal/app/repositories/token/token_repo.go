@@ -0,0 +1,48 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/app/db"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoRepository is MongoDB implementation of repository
+type MongoRepository struct {
+	db *db.Mongo
+}
+
+func NewMongoRepository(m *db.Mongo) *MongoRepository {
+	return &MongoRepository{
+		db: m,
+	}
+}
+
+func (r *MongoRepository) collection() *mongo.Collection {
+	return r.db.Collection("member_tokens")
+}
+
+// Insert MemberToken to DB Mongo
+func (r *MongoRepository) Insert(ctx context.Context, token types.MemberToken) error {
+	_, err := r.collection().InsertOne(ctx, token)
+	return db.MongoError(err)
+}
+
+// Consume mark the unused and unexpired token with given hash and purpose as used and return it,
+// marking is atomic so a token can only be consumed once
+func (r *MongoRepository) Consume(ctx context.Context, hash string, purpose types.TokenPurpose) (*types.MemberToken, error) {
+	filter := bson.M{
+		"hash":       hash,
+		"purpose":    purpose,
+		"used":       false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+
+	var token *types.MemberToken
+	err := r.collection().FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"used": true}}).Decode(&token)
+	return token, db.MongoError(err)
+}
@@ -0,0 +1,148 @@
+package availability
+
+import (
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/trace"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TableRepository is an interface of the table repository used to list tables
+type TableRepository interface {
+	FindByRestaurant(ctx context.Context, restaurantID string) ([]types.Table, error)
+}
+
+// RestaurantRepository is an interface of the restaurant repository used to check the restaurant
+type RestaurantRepository interface {
+	FindByID(ctx context.Context, id string) (*types.Restaurant, error)
+}
+
+// ReservationRepository is an interface of the reservation store used to find busy tables
+type ReservationRepository interface {
+	Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error)
+}
+
+// Service is an availability service
+type Service struct {
+	conf            *configs.Configs
+	em              *configs.ErrorMessage
+	restaurantRepo  RestaurantRepository
+	tableRepo       TableRepository
+	reservationRepo ReservationRepository
+	logger          glog.Logger
+}
+
+// NewService return a new availability service
+func NewService(c *configs.Configs, e *configs.ErrorMessage, rs RestaurantRepository, t TableRepository, r ReservationRepository, l glog.Logger) *Service {
+	return &Service{
+		conf:            c,
+		em:              e,
+		restaurantRepo:  rs,
+		tableRepo:       t,
+		reservationRepo: r,
+		logger:          l,
+	}
+}
+
+// Search return every time slot of the requested day with the tables of the restaurant
+// big enough for the party which are not reserved during the slot, deleted restaurants are not found
+func (s *Service) Search(ctx context.Context, req types.AvailabilityRequest) ([]types.AvailabilitySlot, error) {
+	ctx, span := trace.Start(ctx, "availability.Search")
+	defer span.End()
+	conf := s.conf.Reservation
+	duration := req.Duration
+	if duration == 0 {
+		duration = conf.DefaultDuration
+	}
+	if duration <= 0 || conf.SlotInterval <= 0 {
+		return nil, errors.New("Reservation duration and slot interval must be positive")
+	}
+
+	day := time.Date(req.Date.Year(), req.Date.Month(), req.Date.Day(), 0, 0, 0, 0, req.Date.Location())
+	opening, closing := day.Add(conf.OpenTime), day.Add(conf.CloseTime)
+
+	restaurant, err := s.restaurantRepo.FindByID(ctx, req.RestaurantID)
+	if err != nil {
+		s.logger.Errorf("Restaurant is not existed, err: %v", err)
+		return nil, errors.Wrap(err, "Restaurant not existed, can't search availability")
+	}
+	if restaurant.DelFlg {
+		return nil, apperr.New(apperr.NotFound, "database.data_not_found", "Restaurant is deleted, can't search availability")
+	}
+
+	tables, err := s.tableRepo.FindByRestaurant(ctx, req.RestaurantID)
+	if err != nil {
+		s.logger.Errorf("Can't find tables, err: %v", err)
+		return nil, errors.Wrap(err, "Can't find tables")
+	}
+
+	if len(tables) == 0 {
+		return freeSlots(tables, nil, req.PartySize, opening, closing, conf.SlotInterval, duration), nil
+	}
+	tableIDs := make([]string, 0, len(tables))
+	for _, t := range tables {
+		tableIDs = append(tableIDs, t.ID.Hex())
+	}
+	reservations, err := s.reservationRepo.Find(ctx, types.ReservationFilter{TableIDs: tableIDs, From: opening, To: closing})
+	if err != nil {
+		s.logger.Errorf("Can't find reservations, err: %v", err)
+		return nil, errors.Wrap(err, "Can't find reservations")
+	}
+
+	return freeSlots(tables, reservations, req.PartySize, opening, closing, conf.SlotInterval, duration), nil
+}
+
+// freeSlots split [opening, closing) into slots of given duration starting every interval
+// and keep for each of them the tables able to seat the party that are not reserved,
+// the smallest fitting tables come first
+func freeSlots(tables []types.Table, reservations []types.Reservation, partySize int, opening, closing time.Time, interval, duration time.Duration) []types.AvailabilitySlot {
+	candidates := []types.Table{}
+	for _, t := range tables {
+		if !t.DelFlg && t.Slots >= partySize {
+			candidates = append(candidates, t)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Slots < candidates[j].Slots
+	})
+
+	busy := map[string][]types.Reservation{}
+	for _, r := range reservations {
+		if !r.DelFlg {
+			busy[r.TableID.Hex()] = append(busy[r.TableID.Hex()], r)
+		}
+	}
+
+	slots := []types.AvailabilitySlot{}
+	for start := opening; !start.Add(duration).After(closing); start = start.Add(interval) {
+		end := start.Add(duration)
+		slot := types.AvailabilitySlot{
+			StartTime: start,
+			EndTime:   end,
+			Tables:    []types.Table{},
+		}
+		for _, t := range candidates {
+			if isFree(busy[t.ID.Hex()], start, end) {
+				slot.Tables = append(slot.Tables, t)
+			}
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// isFree return true if none of the reservations overlaps [start, end)
+func isFree(reservations []types.Reservation, start, end time.Time) bool {
+	for _, r := range reservations {
+		if r.StartTime.Before(end) && r.EndTime.After(start) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+package availability
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"booking/configs"
+	reservationRepository "booking/internal/app/repositories/reservation"
+	restaurantRepository "booking/internal/app/repositories/restaurant"
+	tableRepository "booking/internal/app/repositories/table"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newRepositories return memory repositories holding given restaurants and tables
+func newRepositories(t *testing.T, restaurants []types.Restaurant, tables []types.Table) (*restaurantRepository.MemoryRepository, *tableRepository.MemoryRepository) {
+	t.Helper()
+	restaurantRepo, tableRepo := restaurantRepository.NewMemoryRepository(), tableRepository.NewMemoryRepository()
+	for _, r := range restaurants {
+		if err := restaurantRepo.Insert(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, table := range tables {
+		if err := tableRepo.Insert(context.Background(), table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return restaurantRepo, tableRepo
+}
+
+func TestSearch(t *testing.T) {
+	day := time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+	at := func(hour, min int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+
+	restaurant, other := primitive.NewObjectID(), primitive.NewObjectID()
+	small := types.Table{ID: primitive.NewObjectID(), RestaurantID: restaurant, Slots: 2}
+	large := types.Table{ID: primitive.NewObjectID(), RestaurantID: restaurant, Slots: 6}
+	deleted := types.Table{ID: primitive.NewObjectID(), RestaurantID: restaurant, Slots: 4, DelFlg: true}
+	elsewhere := types.Table{ID: primitive.NewObjectID(), RestaurantID: other, Slots: 4}
+	restaurants, tables := newRepositories(t,
+		[]types.Restaurant{{ID: restaurant}, {ID: other}},
+		[]types.Table{large, small, deleted, elsewhere})
+
+	reserve := func(table types.Table, start, end time.Time, cancelled bool) types.Reservation {
+		return types.Reservation{ID: primitive.NewObjectID(), TableID: table.ID, StartTime: start, EndTime: end, DelFlg: cancelled}
+	}
+
+	conf := &configs.Configs{}
+	conf.Reservation = configs.Reservation{
+		OpenTime:        10 * time.Hour,
+		CloseTime:       12 * time.Hour,
+		SlotInterval:    30 * time.Minute,
+		DefaultDuration: time.Hour,
+	}
+
+	type slot struct {
+		start  time.Time
+		tables []types.Table
+	}
+	tests := []struct {
+		name         string
+		reservations []types.Reservation
+		req          types.AvailabilityRequest
+		want         []slot
+	}{
+		{
+			name: "all fitting tables are free, smallest first",
+			req:  types.AvailabilityRequest{Date: at(15, 0), PartySize: 2},
+			want: []slot{
+				{at(10, 0), []types.Table{small, large}},
+				{at(10, 30), []types.Table{small, large}},
+				{at(11, 0), []types.Table{small, large}},
+			},
+		},
+		{
+			name: "tables without enough slots are skipped",
+			req:  types.AvailabilityRequest{Date: day, PartySize: 3, Duration: 90 * time.Minute},
+			want: []slot{
+				{at(10, 0), []types.Table{large}},
+				{at(10, 30), []types.Table{large}},
+			},
+		},
+		{
+			name: "party bigger than every table",
+			req:  types.AvailabilityRequest{Date: day, PartySize: 8, Duration: 2 * time.Hour},
+			want: []slot{
+				{at(10, 0), []types.Table{}},
+			},
+		},
+		{
+			name: "reserved tables are busy during overlapping slots only",
+			reservations: []types.Reservation{
+				reserve(small, at(10, 0), at(10, 45), false),
+				reserve(large, at(11, 30), at(13, 0), false),
+			},
+			req: types.AvailabilityRequest{Date: day, PartySize: 2},
+			want: []slot{
+				{at(10, 0), []types.Table{large}},
+				{at(10, 30), []types.Table{large}},
+				{at(11, 0), []types.Table{small}},
+			},
+		},
+		{
+			name: "reservation ending when slot starts does not overlap",
+			reservations: []types.Reservation{
+				reserve(small, at(9, 0), at(10, 30), false),
+			},
+			req: types.AvailabilityRequest{Date: day, PartySize: 1, Duration: 30 * time.Minute},
+			want: []slot{
+				{at(10, 0), []types.Table{large}},
+				{at(10, 30), []types.Table{small, large}},
+				{at(11, 0), []types.Table{small, large}},
+				{at(11, 30), []types.Table{small, large}},
+			},
+		},
+		{
+			name: "cancelled reservations are ignored",
+			reservations: []types.Reservation{
+				reserve(small, at(10, 0), at(12, 0), true),
+			},
+			req: types.AvailabilityRequest{Date: day, PartySize: 2, Duration: 2 * time.Hour},
+			want: []slot{
+				{at(10, 0), []types.Table{small, large}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reservations := reservationRepository.NewMemoryRepository()
+			for _, r := range tc.reservations {
+				if err := reservations.Insert(context.Background(), r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tc.req.RestaurantID = restaurant.Hex()
+			srv := NewService(conf, &configs.ErrorMessage{}, restaurants, tables, reservations, glog.New())
+			slots, err := srv.Search(context.Background(), tc.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			duration := tc.req.Duration
+			if duration == 0 {
+				duration = conf.Reservation.DefaultDuration
+			}
+			got := []slot{}
+			for _, s := range slots {
+				if !s.EndTime.Equal(s.StartTime.Add(duration)) {
+					t.Errorf("slot %v ends at %v; expected duration %v", s.StartTime, s.EndTime, duration)
+				}
+				got = append(got, slot{s.StartTime, s.Tables})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Search() = %v; expected %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchInvalidConfig(t *testing.T) {
+	srv := NewService(&configs.Configs{}, &configs.ErrorMessage{}, restaurantRepository.NewMemoryRepository(),
+		tableRepository.NewMemoryRepository(), reservationRepository.NewMemoryRepository(), glog.New())
+	if _, err := srv.Search(context.Background(), types.AvailabilityRequest{Date: time.Now(), PartySize: 2}); err == nil {
+		t.Errorf("Search() without slot interval and duration should fail")
+	}
+}
+
+func TestSearchDeletedRestaurant(t *testing.T) {
+	conf := &configs.Configs{}
+	conf.Reservation = configs.Reservation{OpenTime: 10 * time.Hour, CloseTime: 12 * time.Hour, SlotInterval: 30 * time.Minute, DefaultDuration: time.Hour}
+	restaurant := primitive.NewObjectID()
+	restaurants, tables := newRepositories(t,
+		[]types.Restaurant{{ID: restaurant, DelFlg: true}},
+		[]types.Table{{ID: primitive.NewObjectID(), RestaurantID: restaurant, Slots: 4}})
+	srv := NewService(conf, &configs.ErrorMessage{}, restaurants, tables, reservationRepository.NewMemoryRepository(), glog.New())
+
+	for _, id := range []string{restaurant.Hex(), primitive.NewObjectID().Hex()} {
+		_, err := srv.Search(context.Background(), types.AvailabilityRequest{RestaurantID: id, Date: time.Now(), PartySize: 2})
+		if kind := apperr.KindOf(err); kind != apperr.NotFound {
+			t.Errorf("Search(%s) err = %v, kind %v; expected kind %v", id, err, kind, apperr.NotFound)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package member
+
+import (
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/trace"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrAccountLocked is returned on login of a member locked after too many failed logins
+	ErrAccountLocked = apperr.New(apperr.TooManyRequests, "too_many_requests.account_locked", "account locked")
+	// ErrTooManyAttempts is returned on login too soon after failed ones
+	ErrTooManyAttempts = apperr.New(apperr.TooManyRequests, "too_many_requests.login_attempts", "too many login attempts")
+)
+
+// ThrottledError is returned when a login is refused because of previous failed logins,
+// it matches ErrAccountLocked or ErrTooManyAttempts
+type ThrottledError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%v, retry after %v", e.Err, e.RetryAfter)
+}
+
+// Unwrap return ErrAccountLocked or ErrTooManyAttempts
+func (e *ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// checkIPAttempts refuse logins from an IP waiting after failed logins
+func (s *Service) checkIPAttempts(ip string) error {
+	now := s.now()
+	wait := s.ipAttempts.Wait(ip, now)
+	if wait <= 0 {
+		return nil
+	}
+	return &ThrottledError{Err: ErrTooManyAttempts, RetryAfter: wait}
+}
+
+// checkAccountAttempts refuse logins of a member waiting after failed logins
+func (s *Service) checkAccountAttempts(member types.Member) error {
+	now := s.now()
+	wait := s.accountPolicy.Wait(member.FailedLogins, member.LastFailedLogin, now)
+	if wait <= 0 {
+		return nil
+	}
+	if s.accountPolicy.Locked(member.FailedLogins, member.LastFailedLogin, now) {
+		return &ThrottledError{Err: ErrAccountLocked, RetryAfter: wait}
+	}
+	return &ThrottledError{Err: ErrTooManyAttempts, RetryAfter: wait}
+}
+
+// checkEmailAttempts refuse logins of an email which is not registered like those of a member
+// waiting after failed logins, so responses don't tell which emails are registered
+func (s *Service) checkEmailAttempts(email string) error {
+	now := s.now()
+	key := strings.ToLower(email)
+	wait := s.emailAttempts.Wait(key, now)
+	if wait <= 0 {
+		return nil
+	}
+	if s.emailAttempts.Locked(key, now) {
+		return &ThrottledError{Err: ErrAccountLocked, RetryAfter: wait}
+	}
+	return &ThrottledError{Err: ErrTooManyAttempts, RetryAfter: wait}
+}
+
+// emailFailed count a failed login of an email which is not registered
+func (s *Service) emailFailed(email string) {
+	s.emailAttempts.Fail(strings.ToLower(email), s.now())
+}
+
+// loginFailed count a failed login of the IP and of the member if the email is registered
+func (s *Service) loginFailed(ctx context.Context, member *types.Member, ip string) {
+	metrics.FailedLogins.Inc()
+	now := s.now()
+	s.ipAttempts.Fail(ip, now)
+	if member == nil {
+		return
+	}
+
+	// Failures older than the window are forgotten before counting the new one
+	if member.FailedLogins > 0 && s.accountPolicy.Failures(member.FailedLogins, member.LastFailedLogin, now) == 0 {
+		if err := s.repo.ResetFailedLogins(ctx, member.ID); err != nil {
+			s.logger.Errorf("Can't reset failed logins of member %v, err: %v", member.ID.Hex(), err)
+		}
+	}
+	if err := s.repo.RecordFailedLogin(ctx, member.ID, now); err != nil {
+		s.logger.Errorf("Can't record failed login of member %v, err: %v", member.ID.Hex(), err)
+		return
+	}
+	if s.accountPolicy.Locks(s.accountPolicy.Failures(member.FailedLogins, member.LastFailedLogin, now) + 1) {
+		s.logger.Warnf("Member %v locked after too many failed logins", member.ID.Hex())
+	}
+}
+
+// loginSucceeded forget the failed logins of the IP and of the member
+func (s *Service) loginSucceeded(ctx context.Context, member types.Member, ip string) {
+	s.ipAttempts.Reset(ip)
+	if member.FailedLogins == 0 {
+		return
+	}
+	if err := s.repo.ResetFailedLogins(ctx, member.ID); err != nil {
+		s.logger.Errorf("Can't reset failed logins of member %v, err: %v", member.ID.Hex(), err)
+	}
+}
+
+// Unlock forget the failed logins of a member so the member can log in again,
+// managers can unlock members up to their own role
+func (s *Service) Unlock(ctx context.Context, id string) error {
+	ctx, span := trace.Start(ctx, "member.Unlock")
+	defer span.End()
+	member, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Member %v is not existed, err: %v", id, err)
+		return errors.Wrap(err, "Member not existed, can't unlock member")
+	}
+
+	if !auth.HasRole(ctx, types.RoleManager) || !auth.HasRole(ctx, member.GetRole()) {
+		s.logger.Errorf("Not allowed to unlock member %v", id)
+		return auth.ErrForbidden
+	}
+
+	if err := s.repo.ResetFailedLogins(ctx, member.ID); err != nil {
+		s.logger.Errorf("Failed when unlock member, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Member %v unlocked", id)
+	return nil
+}
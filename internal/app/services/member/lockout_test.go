@@ -0,0 +1,156 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/mail"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newLockoutService(now *time.Time) (*Service, types.Member) {
+	conf := &configs.Configs{}
+	conf.LoginProtection.Account = configs.Throttle{MaxAttempts: 3, Window: time.Hour, BaseDelay: time.Second, MaxDelay: time.Minute, Lockout: 15 * time.Minute}
+	conf.LoginProtection.IP = configs.Throttle{MaxAttempts: 10, Window: time.Hour, Lockout: 15 * time.Minute}
+
+	password, _ := jwt.HashPassword("right-password")
+	member := types.Member{ID: primitive.NewObjectID(), Name: "Guest", Email: "guest@booking.local", Password: password, Role: types.RoleGuest}
+	members := &memoryMembers{members: map[primitive.ObjectID]types.Member{member.ID: member}}
+	srv := NewService(conf, &configs.ErrorMessage{}, members, &memoryTokens{}, &fakeSessions{}, mail.NewMemory(), glog.New())
+	srv.now = func() time.Time { return *now }
+	return srv, member
+}
+
+func retryAfter(t *testing.T, err error, target error) time.Duration {
+	var throttled *ThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, target) {
+		t.Fatalf("Login() error = %v; expected %v", err, target)
+	}
+	return throttled.RetryAfter
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	srv, member := newLockoutService(&now)
+	wrong := types.MemberLogin{Email: member.Email, Password: "wrong-password"}
+	right := types.MemberLogin{Email: member.Email, Password: "right-password"}
+
+	if _, err := srv.Login(ctx, wrong, "10.0.0.1"); err == nil {
+		t.Fatal("Login() with wrong password succeeded")
+	}
+	if wait := retryAfter(t, loginError(srv, right, "10.0.0.2"), ErrTooManyAttempts); wait != time.Second {
+		t.Errorf("RetryAfter after 1 failure = %v; expected 1s", wait)
+	}
+
+	now = now.Add(time.Second)
+	srv.Login(ctx, wrong, "10.0.0.1")
+	now = now.Add(2 * time.Second)
+	srv.Login(ctx, wrong, "10.0.0.1")
+	if wait := retryAfter(t, loginError(srv, right, "10.0.0.2"), ErrAccountLocked); wait != 15*time.Minute {
+		t.Errorf("RetryAfter after 3 failures = %v; expected 15m", wait)
+	}
+
+	now = now.Add(15 * time.Minute)
+	if _, err := srv.Login(ctx, right, "10.0.0.2"); err != nil {
+		t.Fatalf("Login() after lockout error = %v", err)
+	}
+	if failures := srv.repo.(*memoryMembers).members[member.ID].FailedLogins; failures != 0 {
+		t.Errorf("FailedLogins after login = %d; expected 0", failures)
+	}
+}
+
+func TestLoginLockoutOfUnknownEmail(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	srv, _ := newLockoutService(&now)
+	unknown := types.MemberLogin{Email: "unknown@booking.local", Password: "password"}
+
+	// unknown emails are answered like registered ones
+	if err := loginError(srv, unknown, "10.0.0.1"); !errors.Is(err, ErrIncorrectCredentials) {
+		t.Fatalf("Login() error = %v; expected %v", err, ErrIncorrectCredentials)
+	}
+	if wait := retryAfter(t, loginError(srv, unknown, "10.0.0.2"), ErrTooManyAttempts); wait != time.Second {
+		t.Errorf("RetryAfter after 1 failure = %v; expected 1s", wait)
+	}
+
+	now = now.Add(time.Second)
+	srv.Login(ctx, unknown, "10.0.0.1")
+	now = now.Add(2 * time.Second)
+	srv.Login(ctx, unknown, "10.0.0.1")
+	upper := types.MemberLogin{Email: "UNKNOWN@booking.local", Password: "password"}
+	if wait := retryAfter(t, loginError(srv, upper, "10.0.0.2"), ErrAccountLocked); wait != 15*time.Minute {
+		t.Errorf("RetryAfter after 3 failures = %v; expected 15m", wait)
+	}
+}
+
+func TestLoginIPLockout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	srv, member := newLockoutService(&now)
+
+	// unknown emails are throttled on their own too, each is only tried once
+	for i := 0; i < 10; i++ {
+		srv.Login(ctx, types.MemberLogin{Email: fmt.Sprintf("unknown%d@booking.local", i), Password: "password"}, "10.0.0.1")
+	}
+	right := types.MemberLogin{Email: member.Email, Password: "right-password"}
+	retryAfter(t, loginError(srv, right, "10.0.0.1"), ErrTooManyAttempts)
+	if _, err := srv.Login(ctx, right, "10.0.0.2"); err != nil {
+		t.Errorf("Login() from another IP error = %v", err)
+	}
+}
+
+func TestLoginResetsIPFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	srv, member := newLockoutService(&now)
+	right := types.MemberLogin{Email: member.Email, Password: "right-password"}
+
+	// 18 failures from the IP would lock it without the login in between
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 9; i++ {
+			srv.Login(ctx, types.MemberLogin{Email: fmt.Sprintf("unknown%d-%d@booking.local", round, i), Password: "password"}, "10.0.0.1")
+		}
+		if _, err := srv.Login(ctx, right, "10.0.0.1"); err != nil {
+			t.Fatalf("Login() after 9 failures of round %d error = %v", round, err)
+		}
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	srv, member := newLockoutService(&now)
+	wrong := types.MemberLogin{Email: member.Email, Password: "wrong-password"}
+	for i := 0; i < 3; i++ {
+		srv.Login(context.Background(), wrong, "10.0.0.1")
+	}
+
+	staff := auth.NewContext(context.Background(), &types.Claims{ID: primitive.NewObjectID(), Role: types.RoleStaff})
+	if err := srv.Unlock(staff, member.ID.Hex()); err != auth.ErrForbidden {
+		t.Errorf("Unlock() by staff error = %v; expected %v", err, auth.ErrForbidden)
+	}
+
+	manager := auth.NewContext(context.Background(), &types.Claims{ID: primitive.NewObjectID(), Role: types.RoleManager})
+	if err := srv.Unlock(manager, member.ID.Hex()); err != nil {
+		t.Fatalf("Unlock() by manager error = %v", err)
+	}
+	right := types.MemberLogin{Email: member.Email, Password: "right-password"}
+	if _, err := srv.Login(context.Background(), right, "10.0.0.2"); err != nil {
+		t.Errorf("Login() after unlock error = %v", err)
+	}
+}
+
+// loginError return the error of a login
+func loginError(srv *Service, login types.MemberLogin, ip string) error {
+	_, err := srv.Login(context.Background(), login, ip)
+	return err
+}
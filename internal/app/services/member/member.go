@@ -3,9 +3,16 @@ package member
 import (
 	"booking/configs"
 	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/auth"
 	"booking/internal/pkg/glog"
 	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/mail"
+	"booking/internal/pkg/throttle"
+	"booking/internal/pkg/trace"
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,38 +25,101 @@ type Repository interface {
 	Insert(ctx context.Context, Member types.Member) error
 	UpdateMemberByID(ctx context.Context, UpdateMemberRequest types.UpdateMemberRequest) error
 	FindByEmail(ctx context.Context, email string) (*types.Member, error)
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, status types.MemberStatus) error
+	RecordFailedLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error
+	UpdateTOTP(ctx context.Context, id primitive.ObjectID, secret string, enabled bool, recoveryCodes []string) error
+	UseTOTPStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, id primitive.ObjectID, hash string) (bool, error)
 }
 
+// TokenRepository is an interface of the repository of single-use tokens sent by mail
+type TokenRepository interface {
+	Insert(ctx context.Context, token types.MemberToken) error
+	Consume(ctx context.Context, hash string, purpose types.TokenPurpose) (*types.MemberToken, error)
+}
+
+// SessionService is an interface of the session service opening a session on login
+type SessionService interface {
+	Issue(ctx context.Context, member types.Member) (*types.Tokens, error)
+	RevokeAll(ctx context.Context, memberID primitive.ObjectID) error
+}
+
+var (
+	// ErrEmailExists is returned when the email address is used by another member
+	ErrEmailExists = apperr.New(apperr.Conflict, "invalid_value.email_exists", "email exists")
+	// ErrNotVerified is returned on login of a member who has not verified the email address
+	ErrNotVerified = apperr.New(apperr.Forbidden, "invalid_value.email_not_verified", "email address not verified")
+	// ErrInvalidToken is returned when a token sent by mail is unknown, expired or used
+	ErrInvalidToken = apperr.New(apperr.Validation, "invalid_value.invalid_token", "invalid token")
+	// ErrIncorrectCredentials is returned on login with an unknown email or a wrong password
+	ErrIncorrectCredentials = apperr.New(apperr.Unauthorized, "invalid_value.incorrect_password_email", "incorrect email or password")
+)
+
 // Service is an member service
 type Service struct {
-	conf   *configs.Configs
-	em     *configs.ErrorMessage
-	repo   Repository
-	logger glog.Logger
+	conf      *configs.Configs
+	em        *configs.ErrorMessage
+	repo      Repository
+	tokenRepo TokenRepository
+	sessions  SessionService
+	mailer    mail.Sender
+	logger    glog.Logger
+
+	// accountPolicy throttle logins per member, emailAttempts apply it to emails which are not
+	// registered and ipAttempts count failed logins per client IP
+	accountPolicy throttle.Policy
+	emailAttempts *throttle.Tracker
+	ipAttempts    *throttle.Tracker
+	now           func() time.Time
 }
 
 // NewService return a new member service
-func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, l glog.Logger) *Service {
+func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, t TokenRepository, ss SessionService, m mail.Sender, l glog.Logger) *Service {
 	return &Service{
-		conf:   c,
-		em:     e,
-		repo:   r,
-		logger: l,
+		conf:      c,
+		em:        e,
+		repo:      r,
+		tokenRepo: t,
+		sessions:  ss,
+		mailer:    m,
+		logger:    l,
+
+		accountPolicy: throttle.NewPolicy(c.LoginProtection.Account),
+		emailAttempts: throttle.NewTracker(throttle.NewPolicy(c.LoginProtection.Account)),
+		ipAttempts:    throttle.NewTracker(throttle.NewPolicy(c.LoginProtection.IP)),
+		now:           time.Now,
 	}
 }
 
-// Get return given member by his/her id
+// Get return given member by his/her id, staff can see every member
 func (s *Service) Get(ctx context.Context, id string) (*types.Member, error) {
+	ctx, span := trace.Start(ctx, "member.Get")
+	defer span.End()
+	if !auth.IsMember(ctx, id) && !auth.HasRole(ctx, types.RoleStaff) {
+		return nil, auth.ErrForbidden
+	}
 	return s.repo.FindByID(ctx, id)
 }
 
 // Post basic
 func (s *Service) InsertMember(ctx context.Context, memreq types.MemberRequest) (*types.Member, error) {
+	ctx, span := trace.Start(ctx, "member.InsertMember")
+	defer span.End()
+
+	// Members can't grant a role higher than their own
+	if memreq.Role == "" {
+		memreq.Role = types.RoleGuest
+	}
+	if !auth.HasRole(ctx, memreq.Role) {
+		s.logger.Errorf("Not allowed to create member with role %v", memreq.Role)
+		return nil, auth.ErrForbidden
+	}
 
 	// Check email if member is registered
-	if _,err := s.repo.FindByEmail(ctx,memreq.Email); err != nil {
-		s.logger.Errorf("Email is existed !!!", err)
-		return nil , errors.Wrap(err, "Email existed, can't insert member")
+	if s.emailExists(ctx, memreq.Email) {
+		s.logger.Errorf("Email %v is existed !!!", memreq.Email)
+		return nil, ErrEmailExists
 	}
 
 	// Password encryption
@@ -60,6 +130,8 @@ func (s *Service) InsertMember(ctx context.Context, memreq types.MemberRequest)
 		Name:     memreq.Name,
 		Password: memreq.Password,
 		Email:    memreq.Email,
+		Role:     memreq.Role,
+		Status:   types.MemberStatusActive,
 	}
 
 	err := s.repo.Insert(ctx, Member)
@@ -74,17 +146,26 @@ func (s *Service) InsertMember(ctx context.Context, memreq types.MemberRequest)
 
 // Put service update info for member by ID
 func (s *Service) UpdateMemberByID(ctx context.Context, mem types.UpdateMemberRequest) error {
+	ctx, span := trace.Start(ctx, "member.UpdateMemberByID")
+	defer span.End()
 
 	// Check member is existed or not by ID
-	if _,err := s.repo.FindByID(ctx,mem.ID); err != nil {
+	member, err := s.repo.FindByID(ctx, mem.ID)
+	if err != nil {
 		s.logger.Errorf("Member is not existed !!!", err)
 		return errors.Wrap(err, "Member existed, can't update member")
 	}
 
+	// Members can update themselves, managers can update members up to their own role
+	if !auth.IsMember(ctx, mem.ID) && !(auth.HasRole(ctx, types.RoleManager) && auth.HasRole(ctx, member.GetRole())) {
+		s.logger.Errorf("Not allowed to update member %v", mem.ID)
+		return auth.ErrForbidden
+	}
+
 	// Password encryption
 	mem.Password, _ = jwt.HashPassword(mem.Password)
 
-	err := s.repo.UpdateMemberByID(ctx, mem)
+	err = s.repo.UpdateMemberByID(ctx, mem)
 
 	if err != nil {
 		s.logger.Errorf("Failed when update member by id", err)
@@ -95,32 +176,73 @@ func (s *Service) UpdateMemberByID(ctx context.Context, mem types.UpdateMemberRe
 	return err
 }
 
-func (s *Service) Login(ctx context.Context, MemberLogin types.MemberLogin) (*types.MemberResponseSignUp, error) {
+// Login check the credentials of a member logging in from the given client IP and open a session,
+// failed logins delay then lock further attempts of the account and of the IP. Members with 2FA
+// get a challenge token to send with a code to LoginTwoFactor instead of a session.
+func (s *Service) Login(ctx context.Context, MemberLogin types.MemberLogin, ip string) (*types.MemberResponseSignUp, error) {
+	ctx, span := trace.Start(ctx, "member.Login")
+	defer span.End()
+
+	if err := s.checkIPAttempts(ip); err != nil {
+		s.logger.Errorf("Login from %v throttled, err: %v", ip, err)
+		return nil, err
+	}
 
 	member, err := s.repo.FindByEmail(ctx, MemberLogin.Email)
+	if apperr.KindOf(err) == apperr.NotFound {
+		if err := s.checkEmailAttempts(MemberLogin.Email); err != nil {
+			s.logger.Errorf("Login of %v throttled, err: %v", MemberLogin.Email, err)
+			return nil, err
+		}
+		s.logger.Errorf("Email %v not found", MemberLogin.Email)
+		s.loginFailed(ctx, nil, ip)
+		s.emailFailed(MemberLogin.Email)
+		return nil, ErrIncorrectCredentials
+	}
 	if err != nil {
-		s.logger.Errorf("Not found email exits", err)
-		return nil, errors.Wrap(errors.New("Not found email exits"), "Email not exists, can't find Member")
+		return nil, errors.Wrap(err, "Can't find member")
+	}
+
+	if err := s.checkAccountAttempts(*member); err != nil {
+		s.logger.Errorf("Login of %v throttled, err: %v", MemberLogin.Email, err)
+		return nil, err
 	}
 
 	if !jwt.IsCorrectPassword(MemberLogin.Password, member.Password) {
-		s.logger.Errorf("Password incorrect", MemberLogin.Email)
-		return nil, errors.Wrap(errors.New("Password isn't like password from database"), "Password incorrect")
+		s.logger.Errorf("Password of %v incorrect", MemberLogin.Email)
+		s.loginFailed(ctx, member, ip)
+		return nil, ErrIncorrectCredentials
+	}
+
+	if !member.IsActive() {
+		s.logger.Errorf("Email %v is not verified", MemberLogin.Email)
+		return nil, ErrNotVerified
+	}
+
+	// Failed logins are only forgotten after the second factor so codes can't be guessed
+	// endlessly by someone knowing the password
+	if member.TOTPEnabled {
+		return s.challenge(ctx, *member)
 	}
 
-	var tokenString string
-	tokenString, error := jwt.GenToken(types.MemberFieldInToken{
-		ID:    member.ID,
-		Name:  member.Name,
-		Email: member.Email}, s.conf.Jwt.Duration)
+	return s.openSession(ctx, *member, ip)
+}
+
+// openSession issue the tokens of a member who logged in from ip
+func (s *Service) openSession(ctx context.Context, member types.Member, ip string) (*types.MemberResponseSignUp, error) {
+	s.loginSucceeded(ctx, member, ip)
+
+	tokens, error := s.sessions.Issue(ctx, member)
 
 	if error != nil {
-		s.logger.Errorf("Can not gen token", error)
-		return nil, errors.Wrap(error, "Can't gen token")
+		s.logger.Errorf("Can not open session", error)
+		return nil, errors.Wrap(error, "Can't open session")
 	}
 	s.logger.Infof("Login completed ", member.Email)
+	metrics.Logins.Inc()
 	return &types.MemberResponseSignUp{
-		Name:  member.Name,
-		Email: member.Email,
-		Token: tokenString}, nil
+		Name:         member.Name,
+		Email:        member.Email,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken}, nil
 }
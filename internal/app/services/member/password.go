@@ -0,0 +1,75 @@
+package member
+
+import (
+	"booking/internal/app/types"
+	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/mail"
+	"booking/internal/pkg/secret"
+	"booking/internal/pkg/trace"
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// ForgotPassword send a password reset link to the member registered with the email address.
+// Unknown addresses are not reported so the endpoint can't be used to find members.
+func (s *Service) ForgotPassword(ctx context.Context, email string) error {
+	ctx, span := trace.Start(ctx, "member.ForgotPassword")
+	defer span.End()
+	member, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		s.logger.Infof("Password reset requested for unknown email %v", email)
+		return nil
+	}
+
+	token, err := s.issueToken(ctx, *member, types.TokenPurposeResetPassword, s.conf.MemberToken.ResetPasswordDuration)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.conf.Mail.BaseURL, url.QueryEscape(token))
+	msg := mail.Message{
+		To:      member.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nA password reset was requested for your account. Open the link below to choose a new password:\n%s\n\nThe link expires in %v. If you did not request it, you can ignore this email.\n",
+			member.Name, link, s.conf.MemberToken.ResetPasswordDuration),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Errorf("Can't send password reset mail, err: %v", err)
+		return errors.Wrap(err, "Can't send password reset mail")
+	}
+
+	s.logger.Infof("Password reset requested for member %v", member.ID.Hex())
+	return nil
+}
+
+// ResetPassword change the password of the member the reset token was sent to
+// and end all of the member's sessions
+func (s *Service) ResetPassword(ctx context.Context, req types.ResetPasswordRequest) error {
+	ctx, span := trace.Start(ctx, "member.ResetPassword")
+	defer span.End()
+	memberToken, err := s.tokenRepo.Consume(ctx, secret.Hash(req.Token), types.TokenPurposeResetPassword)
+	if err != nil {
+		s.logger.Errorf("Password reset token is not valid, err: %v", err)
+		return ErrInvalidToken
+	}
+
+	password, _ := jwt.HashPassword(req.Password)
+	err = s.repo.UpdateMemberByID(ctx, types.UpdateMemberRequest{
+		ID:       memberToken.MemberID.Hex(),
+		Password: password,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed when reset password, err: %v", err)
+		return err
+	}
+
+	if err := s.sessions.RevokeAll(ctx, memberToken.MemberID); err != nil {
+		return err
+	}
+
+	s.logger.Infof("Password of member %v reset", memberToken.MemberID.Hex())
+	return nil
+}
@@ -0,0 +1,59 @@
+package member
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/mail"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestForgotAndResetPassword(t *testing.T) {
+	ctx := context.Background()
+	conf := &configs.Configs{}
+	conf.Mail.BaseURL = "http://booking.local"
+	conf.MemberToken.ResetPasswordDuration = time.Hour
+
+	password, _ := jwt.HashPassword("old-password")
+	member := types.Member{ID: primitive.NewObjectID(), Name: "Staff", Email: "staff@booking.local", Password: password, Status: types.MemberStatusActive}
+	members := &memoryMembers{members: map[primitive.ObjectID]types.Member{member.ID: member}}
+	sessions := &fakeSessions{}
+	mailer := mail.NewMemory()
+	srv := NewService(conf, &configs.ErrorMessage{}, members, &memoryTokens{}, sessions, mailer, glog.New())
+
+	if err := srv.ForgotPassword(ctx, "unknown@booking.local"); err != nil {
+		t.Errorf("ForgotPassword() of unknown email error = %v", err)
+	}
+	if err := srv.ForgotPassword(ctx, member.Email); err != nil {
+		t.Fatalf("ForgotPassword() error = %v", err)
+	}
+	messages := mailer.Messages()
+	if len(messages) != 1 || messages[0].To != member.Email {
+		t.Fatalf("sent messages = %+v; expected one reset mail", messages)
+	}
+	token := tokenInMail(t, messages[0], "/reset-password")
+
+	reset := types.ResetPasswordRequest{Token: token, Password: "new-password"}
+	if err := srv.ResetPassword(ctx, reset); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if err := srv.ResetPassword(ctx, reset); err != ErrInvalidToken {
+		t.Errorf("ResetPassword() twice error = %v; expected %v", err, ErrInvalidToken)
+	}
+	if len(sessions.revoked) != 1 || sessions.revoked[0] != member.ID {
+		t.Errorf("revoked sessions of %v; expected %v", sessions.revoked, member.ID)
+	}
+
+	if _, err := srv.Login(ctx, types.MemberLogin{Email: member.Email, Password: "old-password"}, "127.0.0.1"); err == nil {
+		t.Errorf("Login() with old password succeeded")
+	}
+	if _, err := srv.Login(ctx, types.MemberLogin{Email: member.Email, Password: "new-password"}, "127.0.0.1"); err != nil {
+		t.Errorf("Login() with new password error = %v", err)
+	}
+}
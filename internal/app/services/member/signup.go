@@ -0,0 +1,113 @@
+package member
+
+import (
+	"booking/internal/app/types"
+	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/mail"
+	"booking/internal/pkg/secret"
+	"booking/internal/pkg/trace"
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SignUp create an unverified guest and send a verification link to the email address
+func (s *Service) SignUp(ctx context.Context, signUp types.MemberSignUp) (*types.MemberResponse, error) {
+	ctx, span := trace.Start(ctx, "member.SignUp")
+	defer span.End()
+
+	// Check email if member is registered
+	if s.emailExists(ctx, signUp.Email) {
+		s.logger.Errorf("Email %v is existed !!!", signUp.Email)
+		return nil, ErrEmailExists
+	}
+
+	password, _ := jwt.HashPassword(signUp.Password)
+	Member := types.Member{
+		ID:       primitive.NewObjectID(),
+		Name:     signUp.Name,
+		Password: password,
+		Email:    signUp.Email,
+		Role:     types.RoleGuest,
+		Status:   types.MemberStatusPending,
+	}
+
+	if err := s.repo.Insert(ctx, Member); err != nil {
+		s.logger.Errorf("Can't create member, err: %v", err)
+		return nil, errors.Wrap(err, "Can't create member")
+	}
+
+	token, err := s.issueToken(ctx, Member, types.TokenPurposeVerifyEmail, s.conf.MemberToken.VerifyEmailDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s/verify?token=%s", s.conf.Mail.BaseURL, url.QueryEscape(token))
+	msg := mail.Message{
+		To:      Member.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening the link below:\n%s\n\nThe link expires in %v.\n",
+			Member.Name, link, s.conf.MemberToken.VerifyEmailDuration),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Errorf("Can't send verification mail, err: %v", err)
+		return nil, errors.Wrap(err, "Can't send verification mail")
+	}
+
+	s.logger.Infof("Sign up of %v succesfully!!!", Member.ID.Hex())
+	return &types.MemberResponse{
+		Name:  Member.Name,
+		Email: Member.Email}, nil
+}
+
+// Verify activate the account of the member the verification token was sent to
+func (s *Service) Verify(ctx context.Context, token string) error {
+	ctx, span := trace.Start(ctx, "member.Verify")
+	defer span.End()
+	memberToken, err := s.tokenRepo.Consume(ctx, secret.Hash(token), types.TokenPurposeVerifyEmail)
+	if err != nil {
+		s.logger.Errorf("Verification token is not valid, err: %v", err)
+		return ErrInvalidToken
+	}
+
+	if err := s.repo.UpdateStatus(ctx, memberToken.MemberID, types.MemberStatusActive); err != nil {
+		s.logger.Errorf("Failed when activate member, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Member %v verified", memberToken.MemberID.Hex())
+	return nil
+}
+
+// issueToken store the hash of a new single-use token for the member and return the token
+func (s *Service) issueToken(ctx context.Context, member types.Member, purpose types.TokenPurpose, duration time.Duration) (string, error) {
+	token, err := secret.NewToken()
+	if err != nil {
+		return "", errors.Wrap(err, "Can't gen token")
+	}
+
+	memberToken := types.MemberToken{
+		ID:        primitive.NewObjectID(),
+		MemberID:  member.ID,
+		Purpose:   purpose,
+		Hash:      secret.Hash(token),
+		ExpiresAt: time.Now().Add(duration),
+		Used:      false,
+		CreateAt:  time.Now(),
+	}
+	if err := s.tokenRepo.Insert(ctx, memberToken); err != nil {
+		s.logger.Errorf("Can't store %v token, err: %v", purpose, err)
+		return "", errors.Wrap(err, "Can't store token")
+	}
+	return token, nil
+}
+
+// emailExists return true if a member is registered with the email address
+func (s *Service) emailExists(ctx context.Context, email string) bool {
+	member, err := s.repo.FindByEmail(ctx, email)
+	return err == nil && member != nil
+}
@@ -0,0 +1,224 @@
+package member
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/mail"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errNotFound is the not found error repositories return
+var errNotFound = apperr.New(apperr.NotFound, "database.data_not_found", "not found")
+
+type memoryMembers struct {
+	mu      sync.Mutex
+	members map[primitive.ObjectID]types.Member
+}
+
+func (m *memoryMembers) FindByID(ctx context.Context, id string) (*types.Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	member, ok := m.members[objectID]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &member, nil
+}
+
+func (m *memoryMembers) Insert(ctx context.Context, member types.Member) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.members[member.ID] = member
+	return nil
+}
+
+func (m *memoryMembers) UpdateMemberByID(ctx context.Context, req types.UpdateMemberRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	objectID, _ := primitive.ObjectIDFromHex(req.ID)
+	member, ok := m.members[objectID]
+	if !ok {
+		return errNotFound
+	}
+	member.Password = req.Password
+	m.members[objectID] = member
+	return nil
+}
+
+func (m *memoryMembers) FindByEmail(ctx context.Context, email string) (*types.Member, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, member := range m.members {
+		if member.Email == email {
+			return &member, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (m *memoryMembers) UpdateStatus(ctx context.Context, id primitive.ObjectID, status types.MemberStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := m.members[id]
+	member.Status = status
+	m.members[id] = member
+	return nil
+}
+
+func (m *memoryMembers) RecordFailedLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := m.members[id]
+	member.FailedLogins++
+	member.LastFailedLogin = at
+	m.members[id] = member
+	return nil
+}
+
+func (m *memoryMembers) ResetFailedLogins(ctx context.Context, id primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := m.members[id]
+	member.FailedLogins = 0
+	member.LastFailedLogin = time.Time{}
+	m.members[id] = member
+	return nil
+}
+
+func (m *memoryMembers) UpdateTOTP(ctx context.Context, id primitive.ObjectID, secret string, enabled bool, recoveryCodes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := m.members[id]
+	member.TOTPSecret, member.TOTPEnabled, member.RecoveryCodes = secret, enabled, recoveryCodes
+	m.members[id] = member
+	return nil
+}
+
+func (m *memoryMembers) UseTOTPStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := m.members[id]
+	if member.TOTPLastStep >= step {
+		return false, nil
+	}
+	member.TOTPLastStep = step
+	m.members[id] = member
+	return true, nil
+}
+
+func (m *memoryMembers) UseRecoveryCode(ctx context.Context, id primitive.ObjectID, hash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	member := m.members[id]
+	for i, code := range member.RecoveryCodes {
+		if code == hash {
+			member.RecoveryCodes = append(member.RecoveryCodes[:i:i], member.RecoveryCodes[i+1:]...)
+			m.members[id] = member
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryTokens struct {
+	mu     sync.Mutex
+	tokens []types.MemberToken
+}
+
+func (m *memoryTokens) Insert(ctx context.Context, token types.MemberToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *memoryTokens) Consume(ctx context.Context, hash string, purpose types.TokenPurpose) (*types.MemberToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, token := range m.tokens {
+		if token.Hash == hash && token.Purpose == purpose && !token.Used && time.Now().Before(token.ExpiresAt) {
+			m.tokens[i].Used = true
+			return &token, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+type fakeSessions struct {
+	revoked []primitive.ObjectID
+}
+
+func (f *fakeSessions) Issue(ctx context.Context, member types.Member) (*types.Tokens, error) {
+	return &types.Tokens{Token: "token", RefreshToken: "refresh"}, nil
+}
+
+func (f *fakeSessions) RevokeAll(ctx context.Context, memberID primitive.ObjectID) error {
+	f.revoked = append(f.revoked, memberID)
+	return nil
+}
+
+// tokenInMail return the token of the link sent in a mail
+func tokenInMail(t *testing.T, msg mail.Message, path string) string {
+	match := regexp.MustCompile(path + `\?token=(\S+)`).FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("mail has no %s link: %q", path, msg.Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestSignUpAndVerify(t *testing.T) {
+	ctx := context.Background()
+	conf := &configs.Configs{}
+	conf.Mail.BaseURL = "http://booking.local"
+	conf.MemberToken.VerifyEmailDuration = time.Hour
+	mailer := mail.NewMemory()
+	srv := NewService(conf, &configs.ErrorMessage{}, &memoryMembers{members: map[primitive.ObjectID]types.Member{}}, &memoryTokens{}, &fakeSessions{}, mailer, glog.New())
+
+	signUp := types.MemberSignUp{Name: "Guest", Email: "guest@booking.local", Password: "secret-password"}
+	if _, err := srv.SignUp(ctx, signUp); err != nil {
+		t.Fatalf("SignUp() error = %v", err)
+	}
+	if _, err := srv.SignUp(ctx, signUp); err != ErrEmailExists {
+		t.Errorf("SignUp() twice error = %v; expected %v", err, ErrEmailExists)
+	}
+
+	login := types.MemberLogin{Email: signUp.Email, Password: signUp.Password}
+	if _, err := srv.Login(ctx, login, "127.0.0.1"); err != ErrNotVerified {
+		t.Errorf("Login() before verification error = %v; expected %v", err, ErrNotVerified)
+	}
+
+	messages := mailer.Messages()
+	if len(messages) != 1 || messages[0].To != signUp.Email {
+		t.Fatalf("sent messages = %+v; expected one verification mail", messages)
+	}
+	token := tokenInMail(t, messages[0], "/verify")
+
+	if err := srv.Verify(ctx, "unknown"); err != ErrInvalidToken {
+		t.Errorf("Verify() of unknown token error = %v; expected %v", err, ErrInvalidToken)
+	}
+	if err := srv.Verify(ctx, token); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := srv.Verify(ctx, token); err != ErrInvalidToken {
+		t.Errorf("Verify() twice error = %v; expected %v", err, ErrInvalidToken)
+	}
+
+	if _, err := srv.Login(ctx, login, "127.0.0.1"); err != nil {
+		t.Errorf("Login() after verification error = %v", err)
+	}
+}
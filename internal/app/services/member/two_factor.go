@@ -0,0 +1,226 @@
+package member
+
+import (
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/secret"
+	"booking/internal/pkg/totp"
+	"booking/internal/pkg/trace"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultRecoveryCodes is the number of recovery codes when not configured
+const defaultRecoveryCodes = 10
+
+var (
+	// ErrTwoFactorEnabled is returned on enrollment of a member who already uses 2FA
+	ErrTwoFactorEnabled = apperr.New(apperr.Conflict, "invalid_value.two_factor_enabled", "two-factor authentication enabled")
+	// ErrTwoFactorNotEnrolled is returned when a member without 2FA confirms or disables it
+	ErrTwoFactorNotEnrolled = apperr.New(apperr.Validation, "invalid_value.two_factor_not_enrolled", "two-factor authentication not enrolled")
+	// ErrInvalidCode is returned when a TOTP or recovery code is wrong or already used
+	ErrInvalidCode = apperr.New(apperr.Validation, "invalid_value.invalid_two_factor_code", "invalid two-factor code")
+)
+
+// EnrollTwoFactor generate a new TOTP secret for the authenticated member,
+// logins require codes once the enrollment is confirmed with a code
+func (s *Service) EnrollTwoFactor(ctx context.Context) (*types.TwoFactorEnrollment, error) {
+	ctx, span := trace.Start(ctx, "member.EnrollTwoFactor")
+	defer span.End()
+	member, err := s.currentMember(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if member.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	totpSecret, err := totp.NewSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't gen TOTP secret")
+	}
+	if err := s.repo.UpdateTOTP(ctx, member.ID, totpSecret, false, nil); err != nil {
+		s.logger.Errorf("Can't store TOTP secret, err: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Member %v enrolling 2FA", member.ID.Hex())
+	return &types.TwoFactorEnrollment{
+		Secret: totpSecret,
+		URI:    totp.URI(s.conf.TwoFactor.Issuer, member.Email, totpSecret),
+	}, nil
+}
+
+// ConfirmTwoFactor enable 2FA of the authenticated member with a code of the enrolled
+// secret and return new recovery codes, only their hashes are stored
+func (s *Service) ConfirmTwoFactor(ctx context.Context, code string) (*types.RecoveryCodes, error) {
+	ctx, span := trace.Start(ctx, "member.ConfirmTwoFactor")
+	defer span.End()
+	member, err := s.currentMember(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if member.TOTPEnabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if member.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err := s.checkCode(ctx, *member, types.TwoFactorCodeRequest{Code: code}); err != nil {
+		return nil, err
+	}
+
+	count := s.conf.TwoFactor.RecoveryCodes
+	if count <= 0 {
+		count = defaultRecoveryCodes
+	}
+	codes := make([]string, count)
+	hashes := make([]string, count)
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			return nil, errors.Wrap(err, "Can't gen recovery code")
+		}
+		hashes[i] = secret.Hash(codes[i])
+	}
+
+	if err := s.repo.UpdateTOTP(ctx, member.ID, member.TOTPSecret, true, hashes); err != nil {
+		s.logger.Errorf("Can't enable 2FA, err: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Member %v enabled 2FA", member.ID.Hex())
+	return &types.RecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turn 2FA of the authenticated member off with a code or a recovery code
+func (s *Service) DisableTwoFactor(ctx context.Context, req types.TwoFactorCodeRequest) error {
+	ctx, span := trace.Start(ctx, "member.DisableTwoFactor")
+	defer span.End()
+	member, err := s.currentMember(ctx)
+	if err != nil {
+		return err
+	}
+	if !member.TOTPEnabled {
+		return ErrTwoFactorNotEnrolled
+	}
+	if err := s.checkCode(ctx, *member, req); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateTOTP(ctx, member.ID, "", false, nil); err != nil {
+		s.logger.Errorf("Can't disable 2FA, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Member %v disabled 2FA", member.ID.Hex())
+	return nil
+}
+
+// LoginTwoFactor finish the login of a member with 2FA, the challenge token returned by Login
+// is used up even if the code is wrong and wrong codes count as failed logins
+func (s *Service) LoginTwoFactor(ctx context.Context, req types.TwoFactorLoginRequest, ip string) (*types.MemberResponseSignUp, error) {
+	ctx, span := trace.Start(ctx, "member.LoginTwoFactor")
+	defer span.End()
+	if err := s.checkIPAttempts(ip); err != nil {
+		s.logger.Errorf("Login from %v throttled, err: %v", ip, err)
+		return nil, err
+	}
+
+	challenge, err := s.tokenRepo.Consume(ctx, secret.Hash(req.ChallengeToken), types.TokenPurposeLoginChallenge)
+	if err != nil {
+		s.logger.Errorf("Login challenge token is not valid, err: %v", err)
+		return nil, ErrInvalidToken
+	}
+
+	member, err := s.repo.FindByID(ctx, challenge.MemberID.Hex())
+	if err != nil {
+		s.logger.Errorf("Member %v is not existed, err: %v", challenge.MemberID.Hex(), err)
+		return nil, errors.Wrap(err, "Member not existed, can't login")
+	}
+
+	if err := s.checkAccountAttempts(*member); err != nil {
+		s.logger.Errorf("Login of %v throttled, err: %v", member.Email, err)
+		return nil, err
+	}
+
+	if err := s.checkCode(ctx, *member, req.TwoFactorCodeRequest); err != nil {
+		s.loginFailed(ctx, member, ip)
+		return nil, err
+	}
+
+	return s.openSession(ctx, *member, ip)
+}
+
+// challenge return a login challenge token for a member with 2FA
+func (s *Service) challenge(ctx context.Context, member types.Member) (*types.MemberResponseSignUp, error) {
+	token, err := s.issueToken(ctx, member, types.TokenPurposeLoginChallenge, s.conf.MemberToken.LoginChallengeDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Login of %v waits for 2FA code", member.Email)
+	return &types.MemberResponseSignUp{
+		Name:           member.Name,
+		Email:          member.Email,
+		ChallengeToken: token}, nil
+}
+
+// checkCode accept a code of the authenticator app not used before or an unused recovery code
+func (s *Service) checkCode(ctx context.Context, member types.Member, req types.TwoFactorCodeRequest) error {
+	if req.RecoveryCode != "" {
+		ok, err := s.repo.UseRecoveryCode(ctx, member.ID, secret.Hash(normalizeRecoveryCode(req.RecoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.logger.Errorf("Wrong recovery code of member %v", member.ID.Hex())
+			return ErrInvalidCode
+		}
+		s.logger.Infof("Member %v used a recovery code", member.ID.Hex())
+		return nil
+	}
+
+	step, ok := totp.Validate(member.TOTPSecret, req.Code, s.now(), s.conf.TwoFactor.Skew)
+	if !ok {
+		s.logger.Errorf("Wrong 2FA code of member %v", member.ID.Hex())
+		return ErrInvalidCode
+	}
+	ok, err := s.repo.UseTOTPStep(ctx, member.ID, step)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.logger.Errorf("2FA code of member %v used twice", member.ID.Hex())
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// currentMember return the authenticated member
+func (s *Service) currentMember(ctx context.Context) (*types.Member, error) {
+	claims, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+	return s.repo.FindByID(ctx, claims.ID.Hex())
+}
+
+// newRecoveryCode return a random recovery code like 3f9a1-c07e2
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(b)
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode let members type recovery codes in upper case or with spaces
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
@@ -0,0 +1,102 @@
+package member
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/jwt"
+	"booking/internal/pkg/mail"
+	"booking/internal/pkg/totp"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTwoFactor(t *testing.T) {
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	conf := &configs.Configs{}
+	conf.TwoFactor = configs.TwoFactor{Issuer: "Booking", Skew: 1, RecoveryCodes: 2}
+	conf.MemberToken.LoginChallengeDuration = 5 * time.Minute
+
+	password, _ := jwt.HashPassword("right-password")
+	member := types.Member{ID: primitive.NewObjectID(), Name: "Manager", Email: "manager@booking.local", Password: password, Role: types.RoleManager}
+	members := &memoryMembers{members: map[primitive.ObjectID]types.Member{member.ID: member}}
+	srv := NewService(conf, &configs.ErrorMessage{}, members, &memoryTokens{}, &fakeSessions{}, mail.NewMemory(), glog.New())
+	srv.now = func() time.Time { return now }
+
+	ctx := auth.NewContext(context.Background(), &types.Claims{ID: member.ID, Role: member.Role})
+	login := types.MemberLogin{Email: member.Email, Password: "right-password"}
+
+	enrollment, err := srv.EnrollTwoFactor(ctx)
+	if err != nil {
+		t.Fatalf("EnrollTwoFactor() error = %v", err)
+	}
+	if enrollment.URI != totp.URI("Booking", member.Email, enrollment.Secret) {
+		t.Errorf("EnrollTwoFactor() URI = %v", enrollment.URI)
+	}
+	if res, _ := srv.Login(ctx, login, "10.0.0.1"); res == nil || res.Token == "" {
+		t.Fatalf("Login() before confirmation = %+v; expected tokens", res)
+	}
+
+	if _, err := srv.ConfirmTwoFactor(ctx, "000000"); err != ErrInvalidCode {
+		t.Errorf("ConfirmTwoFactor() with wrong code error = %v; expected %v", err, ErrInvalidCode)
+	}
+	code, _ := totp.Code(enrollment.Secret, now)
+	codes, err := srv.ConfirmTwoFactor(ctx, code)
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor() error = %v", err)
+	}
+	if len(codes.RecoveryCodes) != 2 {
+		t.Fatalf("ConfirmTwoFactor() = %v; expected 2 recovery codes", codes.RecoveryCodes)
+	}
+	if _, err := srv.EnrollTwoFactor(ctx); err != ErrTwoFactorEnabled {
+		t.Errorf("EnrollTwoFactor() when enabled error = %v; expected %v", err, ErrTwoFactorEnabled)
+	}
+
+	challenge := func() string {
+		res, err := srv.Login(ctx, login, "10.0.0.1")
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if res.Token != "" || res.ChallengeToken == "" {
+			t.Fatalf("Login() with 2FA = %+v; expected only a challenge token", res)
+		}
+		return res.ChallengeToken
+	}
+
+	// the code used to confirm can't be replayed
+	replay := types.TwoFactorLoginRequest{ChallengeToken: challenge(), TwoFactorCodeRequest: types.TwoFactorCodeRequest{Code: code}}
+	if _, err := srv.LoginTwoFactor(ctx, replay, "10.0.0.1"); err != ErrInvalidCode {
+		t.Errorf("LoginTwoFactor() with used code error = %v; expected %v", err, ErrInvalidCode)
+	}
+
+	now = now.Add(totp.Period)
+	code, _ = totp.Code(enrollment.Secret, now)
+	req := types.TwoFactorLoginRequest{ChallengeToken: challenge(), TwoFactorCodeRequest: types.TwoFactorCodeRequest{Code: code}}
+	if res, err := srv.LoginTwoFactor(ctx, req, "10.0.0.1"); err != nil || res.Token == "" {
+		t.Fatalf("LoginTwoFactor() = %+v, %v; expected tokens", res, err)
+	}
+	if _, err := srv.LoginTwoFactor(ctx, req, "10.0.0.1"); err != ErrInvalidToken {
+		t.Errorf("LoginTwoFactor() with used challenge error = %v; expected %v", err, ErrInvalidToken)
+	}
+
+	recovery := types.TwoFactorLoginRequest{ChallengeToken: challenge(), TwoFactorCodeRequest: types.TwoFactorCodeRequest{RecoveryCode: " " + codes.RecoveryCodes[0] + " "}}
+	if _, err := srv.LoginTwoFactor(ctx, recovery, "10.0.0.1"); err != nil {
+		t.Errorf("LoginTwoFactor() with recovery code error = %v", err)
+	}
+	recovery.ChallengeToken = challenge()
+	if _, err := srv.LoginTwoFactor(ctx, recovery, "10.0.0.1"); err != ErrInvalidCode {
+		t.Errorf("LoginTwoFactor() with used recovery code error = %v; expected %v", err, ErrInvalidCode)
+	}
+
+	if err := srv.DisableTwoFactor(ctx, types.TwoFactorCodeRequest{RecoveryCode: codes.RecoveryCodes[1]}); err != nil {
+		t.Fatalf("DisableTwoFactor() error = %v", err)
+	}
+	if res, _ := srv.Login(ctx, login, "10.0.0.1"); res == nil || res.Token == "" {
+		t.Errorf("Login() after disabling 2FA = %+v; expected tokens", res)
+	}
+}
@@ -0,0 +1,227 @@
+package reservation
+
+import (
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/trace"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository is an interface of a reservation repository
+type Repository interface {
+	FindByID(ctx context.Context, id string) (*types.Reservation, error)
+	Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error)
+	Insert(ctx context.Context, Reservation types.Reservation) error
+	Update(ctx context.Context, Reservation types.Reservation) error
+	Delete(ctx context.Context, id string) error
+}
+
+// TableRepository is an interface of the table repository used to check the reserved table
+type TableRepository interface {
+	FindByID(ctx context.Context, id string) (*types.Table, error)
+}
+
+// RestaurantRepository is an interface of the restaurant repository used to check the restaurant of the table
+type RestaurantRepository interface {
+	FindByID(ctx context.Context, id string) (*types.Restaurant, error)
+}
+
+// ErrInvalidPeriod is returned when a reservation does not start before it ends
+var ErrInvalidPeriod = apperr.New(apperr.Validation, "invalid_value.validation_failed", "start time must be before end time")
+
+// Service is an reservation service
+type Service struct {
+	conf           *configs.Configs
+	em             *configs.ErrorMessage
+	repo           Repository
+	tableRepo      TableRepository
+	restaurantRepo RestaurantRepository
+	logger         glog.Logger
+}
+
+// NewService return a new reservation service
+func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, t TableRepository, rs RestaurantRepository, l glog.Logger) *Service {
+	return &Service{
+		conf:           c,
+		em:             e,
+		repo:           r,
+		tableRepo:      t,
+		restaurantRepo: rs,
+		logger:         l,
+	}
+}
+
+// Get return given reservation by its id
+func (s *Service) Get(ctx context.Context, id string) (*types.Reservation, error) {
+	ctx, span := trace.Start(ctx, "reservation.Get")
+	defer span.End()
+	return s.findOwned(ctx, id)
+}
+
+// Find return reservations of a table, optionally overlapping the given period,
+// guests only see their own reservations
+func (s *Service) Find(ctx context.Context, filter types.ReservationFilter) ([]types.Reservation, error) {
+	ctx, span := trace.Start(ctx, "reservation.Find")
+	defer span.End()
+	if !auth.HasRole(ctx, types.RoleStaff) {
+		claims, ok := auth.FromContext(ctx)
+		if !ok {
+			return nil, auth.ErrForbidden
+		}
+		filter.MemberID = claims.ID.Hex()
+	}
+	return s.repo.Find(ctx, filter)
+}
+
+// Post service create a reservation for a table
+func (s *Service) InsertReservation(ctx context.Context, resReq types.ReservationRequest) (*types.Reservation, error) {
+	ctx, span := trace.Start(ctx, "reservation.InsertReservation")
+	defer span.End()
+
+	if !resReq.StartTime.Before(resReq.EndTime) {
+		return nil, ErrInvalidPeriod
+	}
+	table, err := s.checkTable(ctx, resReq.TableID, resReq.PartySize)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberID primitive.ObjectID
+	if claims, ok := auth.FromContext(ctx); ok {
+		memberID = claims.ID
+	}
+
+	Reservation := types.Reservation{
+		ID:        primitive.NewObjectID(),
+		TableID:   table.ID,
+		MemberID:  memberID,
+		Name:      resReq.Name,
+		Phone:     resReq.Phone,
+		PartySize: resReq.PartySize,
+		StartTime: resReq.StartTime,
+		EndTime:   resReq.EndTime,
+		Note:      resReq.Note,
+		DelFlg:    false,
+		CreateAt:  time.Now(),
+		UpdateAt:  time.Now(),
+	}
+
+	if err := s.repo.Insert(ctx, Reservation); err != nil {
+		s.logger.Errorf("Can't create reservation, err: %v", err)
+		return nil, errors.Wrap(err, "Can't create reservation")
+	}
+
+	s.logger.Infof("Create reservation %v succesfully!!!", Reservation.ID.Hex())
+	metrics.ReservationsCreated.Inc()
+	return &Reservation, nil
+}
+
+// Put service update a reservation by ID
+func (s *Service) UpdateReservation(ctx context.Context, resReq types.UpdateReservationRequest) error {
+	ctx, span := trace.Start(ctx, "reservation.UpdateReservation")
+	defer span.End()
+
+	if !resReq.StartTime.Before(resReq.EndTime) {
+		return ErrInvalidPeriod
+	}
+	// Check reservation is existed or not by ID
+	Reservation, err := s.findOwned(ctx, resReq.ID)
+	if err != nil {
+		s.logger.Errorf("Reservation is not existed, err: %v", err)
+		return errors.Wrap(err, "Reservation not existed, can't update reservation")
+	}
+	if Reservation.DelFlg {
+		return apperr.New(apperr.NotFound, "database.data_not_found", "Reservation is deleted, can't update reservation")
+	}
+
+	table, err := s.checkTable(ctx, resReq.TableID, resReq.PartySize)
+	if err != nil {
+		return err
+	}
+
+	Reservation.TableID = table.ID
+	Reservation.Name = resReq.Name
+	Reservation.Phone = resReq.Phone
+	Reservation.PartySize = resReq.PartySize
+	Reservation.StartTime = resReq.StartTime
+	Reservation.EndTime = resReq.EndTime
+	Reservation.Note = resReq.Note
+	Reservation.UpdateAt = time.Now()
+
+	if err := s.repo.Update(ctx, *Reservation); err != nil {
+		s.logger.Errorf("Failed when update reservation by id, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Updated reservation is completed !!!")
+	return nil
+}
+
+// Delete service cancel a reservation by ID
+func (s *Service) DeleteReservation(ctx context.Context, id string) error {
+	ctx, span := trace.Start(ctx, "reservation.DeleteReservation")
+	defer span.End()
+
+	// Check reservation is existed or not by ID
+	reservation, err := s.findOwned(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Reservation is not existed, err: %v", err)
+		return errors.Wrap(err, "Reservation not existed, can't delete reservation")
+	}
+	if reservation.DelFlg {
+		return apperr.New(apperr.NotFound, "database.data_not_found", "Reservation is deleted, can't delete reservation")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.logger.Errorf("Failed when delete reservation by id, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Delete reservation is completed !!!")
+	return nil
+}
+
+// findOwned return given reservation if the authenticated member made it or is staff
+func (s *Service) findOwned(ctx context.Context, id string) (*types.Reservation, error) {
+	reservation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.HasRole(ctx, types.RoleStaff) && !auth.IsMember(ctx, reservation.MemberID.Hex()) {
+		return nil, auth.ErrForbidden
+	}
+	return reservation, nil
+}
+
+// checkTable verify the reserved table and its restaurant are existed and the table is big enough for the party
+func (s *Service) checkTable(ctx context.Context, tableID string, partySize int) (*types.Table, error) {
+	table, err := s.tableRepo.FindByID(ctx, tableID)
+	if err != nil {
+		s.logger.Errorf("Table is not existed, err: %v", err)
+		return nil, errors.Wrap(err, "Table not existed, can't reserve table")
+	}
+	if table.DelFlg {
+		return nil, apperr.New(apperr.NotFound, "database.data_not_found", "Table is deleted, can't reserve table")
+	}
+	restaurant, err := s.restaurantRepo.FindByID(ctx, table.RestaurantID.Hex())
+	if err != nil {
+		s.logger.Errorf("Restaurant is not existed, err: %v", err)
+		return nil, errors.Wrap(err, "Restaurant not existed, can't reserve table")
+	}
+	if restaurant.DelFlg {
+		return nil, apperr.New(apperr.NotFound, "database.data_not_found", "Restaurant is deleted, can't reserve table")
+	}
+	if partySize > table.Slots {
+		return nil, apperr.New(apperr.Validation, "invalid_value.party_too_large", fmt.Sprintf("Table has %d slots, can't seat a party of %d", table.Slots, partySize))
+	}
+	return table, nil
+}
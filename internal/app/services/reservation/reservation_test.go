@@ -0,0 +1,234 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/db"
+	reservationRepository "booking/internal/app/repositories/reservation"
+	restaurantRepository "booking/internal/app/repositories/restaurant"
+	tableRepository "booking/internal/app/repositories/table"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var day = time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)
+
+func at(hour int) time.Time {
+	return day.Add(time.Duration(hour) * time.Hour)
+}
+
+func asMember(id primitive.ObjectID, role types.Role) context.Context {
+	return auth.NewContext(context.Background(), &types.Claims{ID: id, Role: role})
+}
+
+type fixture struct {
+	srv          *Service
+	reservations *reservationRepository.MemoryRepository
+	table        types.Table
+	deleted      types.Table
+	closed       types.Table
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	restaurants := restaurantRepository.NewMemoryRepository()
+	open := types.Restaurant{ID: primitive.NewObjectID(), Name: "Open"}
+	closed := types.Restaurant{ID: primitive.NewObjectID(), Name: "Closed", DelFlg: true}
+	for _, restaurant := range []types.Restaurant{open, closed} {
+		if err := restaurants.Insert(ctx, restaurant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tables := tableRepository.NewMemoryRepository()
+	f := fixture{
+		reservations: reservationRepository.NewMemoryRepository(),
+		table:        types.Table{ID: primitive.NewObjectID(), RestaurantID: open.ID, Slots: 4},
+		deleted:      types.Table{ID: primitive.NewObjectID(), RestaurantID: open.ID, Slots: 4, DelFlg: true},
+		closed:       types.Table{ID: primitive.NewObjectID(), RestaurantID: closed.ID, Slots: 4},
+	}
+	for _, table := range []types.Table{f.table, f.deleted, f.closed} {
+		if err := tables.Insert(ctx, table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.srv = NewService(&configs.Configs{}, &configs.ErrorMessage{}, f.reservations, tables, restaurants, glog.New())
+	return f
+}
+
+func TestInsertReservation(t *testing.T) {
+	guest := primitive.NewObjectID()
+	tests := []struct {
+		name string
+		req  func(f fixture) types.ReservationRequest
+		kind apperr.Kind
+		err  error
+	}{
+		{
+			name: "reserved",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 4, StartTime: at(19), EndTime: at(21)}
+			},
+		},
+		{
+			name: "deleted table",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.deleted.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(19), EndTime: at(21)}
+			},
+			kind: apperr.NotFound,
+		},
+		{
+			name: "table of deleted restaurant",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.closed.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(19), EndTime: at(21)}
+			},
+			kind: apperr.NotFound,
+		},
+		{
+			name: "unknown table",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: primitive.NewObjectID().Hex(), Name: "Lan", PartySize: 2, StartTime: at(19), EndTime: at(21)}
+			},
+			kind: apperr.NotFound,
+		},
+		{
+			name: "party over slots",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 5, StartTime: at(19), EndTime: at(21)}
+			},
+			kind: apperr.Validation,
+		},
+		{
+			name: "start after end",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(21), EndTime: at(19)}
+			},
+			kind: apperr.Validation,
+			err:  ErrInvalidPeriod,
+		},
+		{
+			name: "start at end",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(19), EndTime: at(19)}
+			},
+			kind: apperr.Validation,
+			err:  ErrInvalidPeriod,
+		},
+		{
+			name: "overlap",
+			req: func(f fixture) types.ReservationRequest {
+				return types.ReservationRequest{TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(18), EndTime: at(20)}
+			},
+			kind: apperr.Conflict,
+			err:  db.ErrOverlap,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := asMember(guest, types.RoleGuest)
+			if tt.name == "overlap" {
+				existing := types.Reservation{ID: primitive.NewObjectID(), TableID: f.table.ID, StartTime: at(19), EndTime: at(21)}
+				if err := f.reservations.Insert(ctx, existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			reservation, err := f.srv.InsertReservation(ctx, tt.req(f))
+			if tt.kind == apperr.Unknown && err != nil {
+				t.Fatalf("InsertReservation() err = %v", err)
+			}
+			if kind := apperr.KindOf(err); kind != tt.kind {
+				t.Fatalf("InsertReservation() err = %v, kind %v; expected kind %v", err, kind, tt.kind)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("InsertReservation() err = %v; expected %v", err, tt.err)
+			}
+			if err == nil && reservation.MemberID != guest {
+				t.Errorf("MemberID = %v; expected the authenticated member %v", reservation.MemberID, guest)
+			}
+		})
+	}
+}
+
+func TestUpdateDeleteReservationOwner(t *testing.T) {
+	owner, other := primitive.NewObjectID(), primitive.NewObjectID()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{"owner", asMember(owner, types.RoleGuest), nil},
+		{"staff", asMember(other, types.RoleStaff), nil},
+		{"other guest", asMember(other, types.RoleGuest), auth.ErrForbidden},
+		{"anonymous", context.Background(), auth.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			existing := types.Reservation{ID: primitive.NewObjectID(), TableID: f.table.ID, MemberID: owner, PartySize: 2, StartTime: at(19), EndTime: at(21)}
+			if err := f.reservations.Insert(context.Background(), existing); err != nil {
+				t.Fatal(err)
+			}
+
+			err := f.srv.UpdateReservation(tt.ctx, types.UpdateReservationRequest{
+				ID: existing.ID.Hex(), TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 3, StartTime: at(20), EndTime: at(22),
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("UpdateReservation() err = %v; expected %v", err, tt.err)
+			}
+			err = f.srv.DeleteReservation(tt.ctx, existing.ID.Hex())
+			if !errors.Is(err, tt.err) {
+				t.Errorf("DeleteReservation() err = %v; expected %v", err, tt.err)
+			}
+
+			stored, err := f.reservations.FindByID(context.Background(), existing.ID.Hex())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed := stored.PartySize == 3 && stored.DelFlg; changed != (tt.err == nil) {
+				t.Errorf("stored reservation = %+v; expected changed %v", stored, tt.err == nil)
+			}
+			if tt.err == nil {
+				err = f.srv.DeleteReservation(tt.ctx, existing.ID.Hex())
+				if kind := apperr.KindOf(err); kind != apperr.NotFound {
+					t.Errorf("DeleteReservation() again err = %v, kind %v; expected kind %v", err, kind, apperr.NotFound)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateReservationOverlap(t *testing.T) {
+	f := newFixture(t)
+	owner := primitive.NewObjectID()
+	ctx := asMember(owner, types.RoleGuest)
+	first := types.Reservation{ID: primitive.NewObjectID(), TableID: f.table.ID, MemberID: owner, StartTime: at(17), EndTime: at(19)}
+	second := types.Reservation{ID: primitive.NewObjectID(), TableID: f.table.ID, MemberID: owner, StartTime: at(19), EndTime: at(21)}
+	for _, r := range []types.Reservation{first, second} {
+		if err := f.reservations.Insert(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := f.srv.UpdateReservation(ctx, types.UpdateReservationRequest{
+		ID: second.ID.Hex(), TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(18), EndTime: at(20),
+	})
+	if !errors.Is(err, db.ErrOverlap) {
+		t.Errorf("UpdateReservation() err = %v; expected %v", err, db.ErrOverlap)
+	}
+	err = f.srv.UpdateReservation(ctx, types.UpdateReservationRequest{
+		ID: second.ID.Hex(), TableID: f.table.ID.Hex(), Name: "Lan", PartySize: 2, StartTime: at(20), EndTime: at(18),
+	})
+	if !errors.Is(err, ErrInvalidPeriod) {
+		t.Errorf("UpdateReservation() err = %v; expected %v", err, ErrInvalidPeriod)
+	}
+}
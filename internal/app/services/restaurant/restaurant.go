@@ -0,0 +1,146 @@
+package restaurant
+
+import (
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/trace"
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository is an interface of a restaurant repository
+type Repository interface {
+	FindByID(ctx context.Context, id string) (*types.Restaurant, error)
+	FindAll(ctx context.Context) ([]types.Restaurant, error)
+	Insert(ctx context.Context, Restaurant types.Restaurant) error
+	UpdateRestaurantByID(ctx context.Context, UpdateRestaurantRequest types.UpdateRestaurantRequest) error
+	DeleteRestaurant(ctx context.Context, DeleteRestaurantRequest types.DeleteRestaurantRequest) error
+}
+
+// TableRepository is an interface of the table repository used to list tables of a restaurant
+type TableRepository interface {
+	FindByRestaurant(ctx context.Context, restaurantID string) ([]types.Table, error)
+}
+
+// Service is an restaurant service
+type Service struct {
+	conf      *configs.Configs
+	em        *configs.ErrorMessage
+	repo      Repository
+	tableRepo TableRepository
+	logger    glog.Logger
+}
+
+// NewService return a new restaurant service
+func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, t TableRepository, l glog.Logger) *Service {
+	return &Service{
+		conf:      c,
+		em:        e,
+		repo:      r,
+		tableRepo: t,
+		logger:    l,
+	}
+}
+
+// Get return given restaurant by its id
+func (s *Service) Get(ctx context.Context, id string) (*types.Restaurant, error) {
+	ctx, span := trace.Start(ctx, "restaurant.Get")
+	defer span.End()
+	return s.repo.FindByID(ctx, id)
+}
+
+// FindAll return all restaurants which are not deleted
+func (s *Service) FindAll(ctx context.Context) ([]types.Restaurant, error) {
+	ctx, span := trace.Start(ctx, "restaurant.FindAll")
+	defer span.End()
+	return s.repo.FindAll(ctx)
+}
+
+// FindTables return the tables of given restaurant
+func (s *Service) FindTables(ctx context.Context, id string) ([]types.Table, error) {
+	ctx, span := trace.Start(ctx, "restaurant.FindTables")
+	defer span.End()
+
+	// Check restaurant is existed or not by ID
+	restaurant, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Restaurant is not existed, err: %v", err)
+		return nil, errors.Wrap(err, "Restaurant not existed, can't find tables")
+	}
+	if restaurant.DelFlg {
+		return nil, apperr.New(apperr.NotFound, "database.data_not_found", "Restaurant is deleted, can't find tables")
+	}
+
+	return s.tableRepo.FindByRestaurant(ctx, id)
+}
+
+// Post basic
+func (s *Service) InsertRestaurant(ctx context.Context, restaurantReq types.RestaurantRequest) (*types.Restaurant, error) {
+	ctx, span := trace.Start(ctx, "restaurant.InsertRestaurant")
+	defer span.End()
+
+	Restaurant := types.Restaurant{
+		ID:       primitive.NewObjectID(),
+		Name:     restaurantReq.Name,
+		Address:  restaurantReq.Address,
+		DelFlg:   false,
+		CreateAt: time.Now(),
+		UpdateAt: time.Now(),
+	}
+
+	err := s.repo.Insert(ctx, Restaurant)
+	if err != nil {
+		s.logger.Errorf("Can't create restaurant, err: %v", err)
+		return nil, errors.Wrap(err, "Can't create restaurant")
+	}
+
+	s.logger.Infof("Create restaurant %v succesfully!!!", Restaurant.ID.Hex())
+	return &Restaurant, nil
+}
+
+// Put service update info for restaurant by ID
+func (s *Service) UpdateRestaurantByID(ctx context.Context, restaurant types.UpdateRestaurantRequest) error {
+	ctx, span := trace.Start(ctx, "restaurant.UpdateRestaurantByID")
+	defer span.End()
+
+	// Check restaurant is existed or not by ID
+	if _, err := s.repo.FindByID(ctx, restaurant.ID); err != nil {
+		s.logger.Errorf("Restaurant is not existed, err: %v", err)
+		return errors.Wrap(err, "Restaurant not existed, can't update restaurant")
+	}
+
+	err := s.repo.UpdateRestaurantByID(ctx, restaurant)
+	if err != nil {
+		s.logger.Errorf("Failed when update restaurant by id, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Updated restaurant is completed !!!")
+	return nil
+}
+
+// Put service delete restaurant by ID
+func (s *Service) DeleteRestaurant(ctx context.Context, restaurant types.DeleteRestaurantRequest) error {
+	ctx, span := trace.Start(ctx, "restaurant.DeleteRestaurant")
+	defer span.End()
+
+	// Check restaurant is existed or not by ID
+	if _, err := s.repo.FindByID(ctx, restaurant.ID); err != nil {
+		s.logger.Errorf("Restaurant is not existed, err: %v", err)
+		return errors.Wrap(err, "Restaurant not existed, can't delete restaurant")
+	}
+
+	err := s.repo.DeleteRestaurant(ctx, restaurant)
+	if err != nil {
+		s.logger.Errorf("Failed when delete restaurant by id, err: %v", err)
+		return err
+	}
+
+	s.logger.Infof("Delete restaurant is completed !!!")
+	return nil
+}
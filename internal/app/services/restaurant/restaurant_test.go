@@ -0,0 +1,56 @@
+package restaurant
+
+import (
+	"context"
+	"testing"
+
+	"booking/configs"
+	restaurantRepository "booking/internal/app/repositories/restaurant"
+	tableRepository "booking/internal/app/repositories/table"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeleteRestaurant(t *testing.T) {
+	ctx := context.Background()
+	tables := tableRepository.NewMemoryRepository()
+	srv := NewService(&configs.Configs{}, &configs.ErrorMessage{}, restaurantRepository.NewMemoryRepository(), tables, glog.New())
+
+	kept, err := srv.InsertRestaurant(ctx, types.RestaurantRequest{Name: "Kept"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := srv.InsertRestaurant(ctx, types.RestaurantRequest{Name: "Deleted"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tables.Insert(ctx, types.Table{ID: primitive.NewObjectID(), RestaurantID: deleted.ID, Slots: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.DeleteRestaurant(ctx, types.DeleteRestaurantRequest{ID: deleted.ID.Hex(), DelFlg: true}); err != nil {
+		t.Fatalf("DeleteRestaurant() err = %v", err)
+	}
+
+	restaurants, err := srv.FindAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restaurants) != 1 || restaurants[0].ID != kept.ID {
+		t.Errorf("FindAll() = %+v; expected only %v", restaurants, kept.ID)
+	}
+	if _, err := srv.FindTables(ctx, deleted.ID.Hex()); apperr.KindOf(err) != apperr.NotFound {
+		t.Errorf("FindTables() of deleted restaurant err = %v; expected kind %v", err, apperr.NotFound)
+	}
+	if _, err := srv.FindTables(ctx, kept.ID.Hex()); err != nil {
+		t.Errorf("FindTables() err = %v", err)
+	}
+
+	err = srv.DeleteRestaurant(ctx, types.DeleteRestaurantRequest{ID: primitive.NewObjectID().Hex(), DelFlg: true})
+	if kind := apperr.KindOf(err); kind != apperr.NotFound {
+		t.Errorf("DeleteRestaurant() of unknown restaurant err = %v, kind %v; expected kind %v", err, kind, apperr.NotFound)
+	}
+}
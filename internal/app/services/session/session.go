@@ -0,0 +1,214 @@
+package session
+
+import (
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/secret"
+	"booking/internal/pkg/trace"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository is an interface of a session repository
+type Repository interface {
+	FindByID(ctx context.Context, id string) (*types.Session, error)
+	Insert(ctx context.Context, Session types.Session) error
+	Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, id primitive.ObjectID) error
+	RevokeByMember(ctx context.Context, memberID primitive.ObjectID) error
+}
+
+// MemberRepository is an interface of the member repository used to renew access tokens
+type MemberRepository interface {
+	FindByID(ctx context.Context, id string) (*types.Member, error)
+}
+
+// Signer is an interface of the signer of access tokens
+type Signer interface {
+	GenToken(member types.MemberFieldInToken, duration time.Duration) (string, error)
+}
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown, expired, revoked or reused
+var ErrInvalidRefreshToken = apperr.New(apperr.Unauthorized, "invalid_value.failed_authentication", "invalid refresh token")
+
+// Service is an session service
+type Service struct {
+	conf       *configs.Configs
+	em         *configs.ErrorMessage
+	repo       Repository
+	memberRepo MemberRepository
+	signer     Signer
+	logger     glog.Logger
+}
+
+// NewService return a new session service
+func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, m MemberRepository, sg Signer, l glog.Logger) *Service {
+	return &Service{
+		conf:       c,
+		em:         e,
+		repo:       r,
+		memberRepo: m,
+		signer:     sg,
+		logger:     l,
+	}
+}
+
+// Issue open a new session for the member and return its first tokens
+func (s *Service) Issue(ctx context.Context, member types.Member) (*types.Tokens, error) {
+	ctx, span := trace.Start(ctx, "session.Issue")
+	defer span.End()
+	sessionID := primitive.NewObjectID()
+	refreshToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't gen refresh token")
+	}
+
+	Session := types.Session{
+		ID:               sessionID,
+		MemberID:         member.ID,
+		RefreshTokenHash: secret.Hash(refreshToken),
+		ExpiresAt:        time.Now().Add(s.conf.Jwt.RefreshDuration),
+		Revoked:          false,
+		CreateAt:         time.Now(),
+		UpdateAt:         time.Now(),
+	}
+	if err := s.repo.Insert(ctx, Session); err != nil {
+		s.logger.Errorf("Can't create session, err: %v", err)
+		return nil, errors.Wrap(err, "Can't create session")
+	}
+
+	return s.tokens(member, sessionID, refreshToken)
+}
+
+// Refresh exchange a refresh token for new tokens, the refresh token can only be used once.
+// Presenting an already used refresh token revokes the session as it has likely been stolen.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*types.Tokens, error) {
+	ctx, span := trace.Start(ctx, "session.Refresh")
+	defer span.End()
+	sessionID, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session, err := s.repo.FindByID(ctx, sessionID.Hex())
+	if apperr.KindOf(err) == apperr.NotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		s.logger.Errorf("Failed when find session, err: %v", err)
+		return nil, err
+	}
+	if session.Revoked || time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	hash := secret.Hash(refreshToken)
+	if !secret.Equal(hash, session.RefreshTokenHash) {
+		s.logger.Warnf("Refresh token of session %v reused, revoking session", sessionID.Hex())
+		if err := s.repo.Revoke(ctx, sessionID); err != nil {
+			s.logger.Errorf("Failed when revoke session, err: %v", err)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	member, err := s.memberRepo.FindByID(ctx, session.MemberID.Hex())
+	if apperr.KindOf(err) == apperr.NotFound {
+		s.logger.Errorf("Member of session is not existed, err: %v", err)
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		s.logger.Errorf("Failed when find member of session, err: %v", err)
+		return nil, err
+	}
+
+	newToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Can't gen refresh token")
+	}
+	rotated, err := s.repo.Rotate(ctx, sessionID, hash, secret.Hash(newToken), time.Now().Add(s.conf.Jwt.RefreshDuration))
+	if err != nil {
+		s.logger.Errorf("Failed when rotate refresh token, err: %v", err)
+		return nil, err
+	}
+	if !rotated {
+		// another refresh with the same token won the race
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.tokens(*member, sessionID, newToken)
+}
+
+// Revoke end the session, tokens issued for it are rejected from now on
+func (s *Service) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := trace.Start(ctx, "session.Revoke")
+	defer span.End()
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		s.logger.Errorf("Failed when revoke session, err: %v", err)
+		return err
+	}
+	s.logger.Infof("Session %v revoked", id.Hex())
+	return nil
+}
+
+// RevokeAll end every session of a member
+func (s *Service) RevokeAll(ctx context.Context, memberID primitive.ObjectID) error {
+	ctx, span := trace.Start(ctx, "session.RevokeAll")
+	defer span.End()
+	if err := s.repo.RevokeByMember(ctx, memberID); err != nil {
+		s.logger.Errorf("Failed when revoke sessions of member, err: %v", err)
+		return err
+	}
+	s.logger.Infof("Sessions of member %v revoked", memberID.Hex())
+	return nil
+}
+
+// IsActive return true if the session exists, is not revoked and has not expired
+func (s *Service) IsActive(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	ctx, span := trace.Start(ctx, "session.IsActive")
+	defer span.End()
+	session, err := s.repo.FindByID(ctx, id.Hex())
+	if err != nil {
+		return false, err
+	}
+	return !session.Revoked && time.Now().Before(session.ExpiresAt), nil
+}
+
+func (s *Service) tokens(member types.Member, sessionID primitive.ObjectID, refreshToken string) (*types.Tokens, error) {
+	token, err := s.signer.GenToken(types.MemberFieldInToken{
+		ID:        member.ID,
+		Name:      member.Name,
+		Email:     member.Email,
+		Role:      member.GetRole(),
+		SessionID: sessionID}, s.conf.Jwt.Duration)
+	if err != nil {
+		s.logger.Errorf("Can not gen token, err: %v", err)
+		return nil, errors.Wrap(err, "Can't gen token")
+	}
+	return &types.Tokens{
+		Token:        token,
+		RefreshToken: refreshToken}, nil
+}
+
+// newRefreshToken return a refresh token of the session, formatted as <session id>.<random>
+func newRefreshToken(sessionID primitive.ObjectID) (string, error) {
+	random, err := secret.NewToken()
+	if err != nil {
+		return "", err
+	}
+	return sessionID.Hex() + "." + random, nil
+}
+
+func parseRefreshToken(refreshToken string) (primitive.ObjectID, bool) {
+	parts := strings.SplitN(refreshToken, ".", 2)
+	if len(parts) != 2 {
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(parts[0])
+	return id, err == nil
+}
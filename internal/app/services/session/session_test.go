@@ -0,0 +1,147 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/jwt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memorySessions map[primitive.ObjectID]*types.Session
+
+func (m memorySessions) FindByID(ctx context.Context, id string) (*types.Session, error) {
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	session, ok := m[objectID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	cp := *session
+	return &cp, nil
+}
+
+func (m memorySessions) Insert(ctx context.Context, session types.Session) error {
+	m[session.ID] = &session
+	return nil
+}
+
+func (m memorySessions) Rotate(ctx context.Context, id primitive.ObjectID, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	session, ok := m[id]
+	if !ok || session.Revoked || session.RefreshTokenHash != oldHash {
+		return false, nil
+	}
+	session.RefreshTokenHash, session.ExpiresAt = newHash, expiresAt
+	return true, nil
+}
+
+func (m memorySessions) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	if session, ok := m[id]; ok {
+		session.Revoked = true
+	}
+	return nil
+}
+
+func (m memorySessions) RevokeByMember(ctx context.Context, memberID primitive.ObjectID) error {
+	for _, session := range m {
+		if session.MemberID == memberID {
+			session.Revoked = true
+		}
+	}
+	return nil
+}
+
+type memoryMembers map[string]types.Member
+
+func (m memoryMembers) FindByID(ctx context.Context, id string) (*types.Member, error) {
+	member, ok := m[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &member, nil
+}
+
+func newTestService() (*Service, types.Member) {
+	member := types.Member{ID: primitive.NewObjectID(), Name: "Staff", Email: "staff@booking.local", Role: types.RoleStaff}
+	conf := &configs.Configs{}
+	conf.Jwt.Duration = time.Minute
+	conf.Jwt.RefreshDuration = time.Hour
+	conf.Jwt.SigningKey = "test"
+	conf.Jwt.Keys = []configs.JwtKey{{ID: "test", Algorithm: "HS256", Secret: "secret"}}
+	keys, err := jwt.NewKeySet(conf.Jwt)
+	if err != nil {
+		panic(err)
+	}
+	srv := NewService(conf, &configs.ErrorMessage{}, memorySessions{}, memoryMembers{member.ID.Hex(): member}, keys, glog.New())
+	return srv, member
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	ctx := context.Background()
+	srv, member := newTestService()
+
+	tokens, err := srv.Issue(ctx, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := srv.Refresh(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if refreshed.RefreshToken == tokens.RefreshToken {
+		t.Errorf("Refresh() did not rotate the refresh token")
+	}
+	if _, err := srv.Refresh(ctx, refreshed.RefreshToken); err != nil {
+		t.Errorf("Refresh() with rotated token error = %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	ctx := context.Background()
+	srv, member := newTestService()
+
+	tokens, err := srv.Issue(ctx, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := srv.Refresh(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := srv.Refresh(ctx, tokens.RefreshToken); err != ErrInvalidRefreshToken {
+		t.Errorf("Refresh() with used token error = %v; expected %v", err, ErrInvalidRefreshToken)
+	}
+	if _, err := srv.Refresh(ctx, refreshed.RefreshToken); err != ErrInvalidRefreshToken {
+		t.Errorf("Refresh() after reuse error = %v; expected %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestRevokedSessionIsNotActive(t *testing.T) {
+	ctx := context.Background()
+	srv, member := newTestService()
+
+	tokens, err := srv.Issue(ctx, member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID, _ := parseRefreshToken(tokens.RefreshToken)
+	if active, err := srv.IsActive(ctx, sessionID); err != nil || !active {
+		t.Fatalf("IsActive() = %v, %v; expected true", active, err)
+	}
+
+	if err := srv.RevokeAll(ctx, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := srv.IsActive(ctx, sessionID); active {
+		t.Errorf("IsActive() = true after revoke; expected false")
+	}
+	if _, err := srv.Refresh(ctx, tokens.RefreshToken); err != ErrInvalidRefreshToken {
+		t.Errorf("Refresh() of revoked session error = %v; expected %v", err, ErrInvalidRefreshToken)
+	}
+}
@@ -3,7 +3,10 @@ package table
 import (
 	"booking/configs"
 	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
 	"booking/internal/pkg/glog"
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/trace"
 	"context"
 
 	"github.com/pkg/errors"
@@ -17,31 +20,52 @@ type Repository interface {
 	Insert(ctx context.Context, Table types.Table) error
 	UpdateTableByID(ctx context.Context, UpdateTableRequest types.UpdateTableRequest) error
 	DeleteTable(ctx context.Context, DeleteTableRequest types.DeleteTableRequest) error
+	FindByRestaurant(ctx context.Context, restaurantID string) ([]types.Table, error)
+}
+
+// RestaurantRepository is an interface of the restaurant repository used to check the owner of a table
+type RestaurantRepository interface {
+	FindByID(ctx context.Context, id string) (*types.Restaurant, error)
 }
 
 // Service is an table service
 type Service struct {
-	conf   *configs.Configs
-	em     *configs.ErrorMessage
-	repo   Repository
-	logger glog.Logger
+	conf           *configs.Configs
+	em             *configs.ErrorMessage
+	repo           Repository
+	restaurantRepo RestaurantRepository
+	logger         glog.Logger
 }
 
 // NewService return a new member service
-func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, l glog.Logger) *Service {
+func NewService(c *configs.Configs, e *configs.ErrorMessage, r Repository, rr RestaurantRepository, l glog.Logger) *Service {
 	return &Service{
-		conf:   c,
-		em:     e,
-		repo:   r,
-		logger: l,
+		conf:           c,
+		em:             e,
+		repo:           r,
+		restaurantRepo: rr,
+		logger:         l,
 	}
 }
 
 // Post basic
 func (s *Service) InsertTable(ctx context.Context, tableReq types.TableRequest) (*types.Table, error){
+	ctx, span := trace.Start(ctx, "table.InsertTable")
+	defer span.End()
+
+	// Check restaurant of the table is existed or not by ID
+	restaurant, err := s.restaurantRepo.FindByID(ctx, tableReq.RestaurantID)
+	if err != nil {
+		s.logger.Errorf("restaurant not found, err: %v", err)
+		return nil, errors.Wrap(err, "Restaurant not existed, can't create table")
+	}
+	if restaurant.DelFlg {
+		return nil, apperr.New(apperr.NotFound, "database.data_not_found", "Restaurant is deleted, can't create table")
+	}
 
 	Table := types.Table{
 		ID:       primitive.NewObjectID(),
+		RestaurantID: restaurant.ID,
 		Status:	  tableReq.Status,
 		Type:	  tableReq.Type,
 		Slots:    tableReq.Slots,
@@ -50,18 +74,21 @@ func (s *Service) InsertTable(ctx context.Context, tableReq types.TableRequest)
 		UpdateAt: time.Now(),
 	}
 
-	err := s.repo.Insert(ctx, Table)
+	err = s.repo.Insert(ctx, Table)
 	if err != nil {
 		s.logger.Errorf("Can't create table", err)
 		return nil, errors.Wrap(err, "Can't create table")
 	}
 
 	s.logger.Infof("Create succesfully!!!", tableReq)
+	metrics.TablesCreated.Inc()
 	return &Table, nil
 }
 
 // Put service update info for table by ID
 func (s *Service) UpdateTableByID(ctx context.Context, table types.UpdateTableRequest) error {
+	ctx, span := trace.Start(ctx, "table.UpdateTableByID")
+	defer span.End()
 
 	// Check table is existed or not by ID
 	if _,err := s.repo.FindByID(ctx,table.ID); err != nil {
@@ -82,6 +109,8 @@ func (s *Service) UpdateTableByID(ctx context.Context, table types.UpdateTableRe
 
 // Put service delete table by ID 
 func (s *Service) DeleteTable(ctx context.Context, table types.DeleteTableRequest) error {
+	ctx, span := trace.Start(ctx, "table.DeleteTable")
+	defer span.End()
 
 	// Check table is existed or not by ID
 	if _,err := s.repo.FindByID(ctx,table.ID); err != nil {
@@ -0,0 +1,56 @@
+package table
+
+import (
+	"context"
+	"testing"
+
+	"booking/configs"
+	restaurantRepository "booking/internal/app/repositories/restaurant"
+	tableRepository "booking/internal/app/repositories/table"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"booking/internal/pkg/glog"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeleteTable(t *testing.T) {
+	ctx := context.Background()
+	restaurants := restaurantRepository.NewMemoryRepository()
+	open := types.Restaurant{ID: primitive.NewObjectID(), Name: "Open"}
+	closed := types.Restaurant{ID: primitive.NewObjectID(), Name: "Closed", DelFlg: true}
+	for _, restaurant := range []types.Restaurant{open, closed} {
+		if err := restaurants.Insert(ctx, restaurant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tables := tableRepository.NewMemoryRepository()
+	srv := NewService(&configs.Configs{}, &configs.ErrorMessage{}, tables, restaurants, glog.New())
+
+	for _, id := range []string{closed.ID.Hex(), primitive.NewObjectID().Hex()} {
+		_, err := srv.InsertTable(ctx, types.TableRequest{RestaurantID: id, Status: "free", Type: "indoor", Slots: 4})
+		if kind := apperr.KindOf(err); kind != apperr.NotFound {
+			t.Errorf("InsertTable() in restaurant %s err = %v, kind %v; expected kind %v", id, err, kind, apperr.NotFound)
+		}
+	}
+
+	table, err := srv.InsertTable(ctx, types.TableRequest{RestaurantID: open.ID.Hex(), Status: "free", Type: "indoor", Slots: 4})
+	if err != nil {
+		t.Fatalf("InsertTable() err = %v", err)
+	}
+	if err := srv.DeleteTable(ctx, types.DeleteTableRequest{ID: table.ID.Hex(), DelFlg: true}); err != nil {
+		t.Fatalf("DeleteTable() err = %v", err)
+	}
+	stored, err := tables.FindByID(ctx, table.ID.Hex())
+	if err != nil || !stored.DelFlg {
+		t.Errorf("FindByID() = %+v, %v; expected a deleted table", stored, err)
+	}
+	if listed, err := tables.FindByRestaurant(ctx, open.ID.Hex()); err != nil || len(listed) != 0 {
+		t.Errorf("FindByRestaurant() = %+v, %v; expected no table", listed, err)
+	}
+
+	err = srv.DeleteTable(ctx, types.DeleteTableRequest{ID: primitive.NewObjectID().Hex(), DelFlg: true})
+	if kind := apperr.KindOf(err); kind != apperr.NotFound {
+		t.Errorf("DeleteTable() of unknown table err = %v, kind %v; expected kind %v", err, kind, apperr.NotFound)
+	}
+}
@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// AvailabilityRequest hold the criteria to search free tables of a day
+type AvailabilityRequest struct {
+	RestaurantID string        `json:"restaurant" validate:"required"`
+	Date         time.Time     `json:"date" validate:"required"`
+	PartySize    int           `json:"party_size" validate:"required,min=1"`
+	Duration     time.Duration `json:"duration" validate:"omitempty,min=1m"`
+}
+
+// AvailabilitySlot hold the tables which are free during a time slot
+type AvailabilitySlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Tables    []Table   `json:"tables"`
+}
@@ -9,5 +9,8 @@ type Claims struct {
 	ID    primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
 	Email string             `json:"email"`
 	Name  string             `json:"name"`
+	Role  Role               `json:"role"`
+	// SessionID is the session the token was issued for, revoking it rejects the token
+	SessionID primitive.ObjectID `json:"sid"`
 	jwt.StandardClaims
 }
@@ -1,6 +1,42 @@
 package types
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Role is the access level of a member
+type Role string
+
+const (
+	RoleOwner   Role = "owner"
+	RoleManager Role = "manager"
+	RoleStaff   Role = "staff"
+	RoleGuest   Role = "guest"
+)
+
+var roleRanks = map[Role]int{
+	RoleGuest:   1,
+	RoleStaff:   2,
+	RoleManager: 3,
+	RoleOwner:   4,
+}
+
+// AtLeast return true if the role grants as much access as the given one,
+// unknown roles grant nothing
+func (r Role) AtLeast(min Role) bool {
+	return roleRanks[r] > 0 && roleRanks[r] >= roleRanks[min]
+}
+
+// MemberStatus is the state of the account of a member
+type MemberStatus string
+
+const (
+	// MemberStatusPending is a signed up member who has not verified the email address yet
+	MemberStatusPending MemberStatus = "pending"
+	MemberStatusActive  MemberStatus = "active"
+)
 
 // member hold information of a member
 type Member struct {
@@ -8,12 +44,38 @@ type Member struct {
 	Name     string             `json:"name" bson:"name" validate:"required"`
 	Password string             `json:"password" bson:"password" validate:"required"`
 	Email    string             `json:"email" bson:"email" validate:"required"`
+	Role     Role               `json:"role" bson:"role"`
+	Status   MemberStatus       `json:"status" bson:"status"`
+	// FailedLogins count consecutive failed logins, the last one happened at LastFailedLogin
+	FailedLogins    int       `json:"failed_logins" bson:"failed_logins,omitempty"`
+	LastFailedLogin time.Time `json:"last_failed_login" bson:"last_failed_login,omitempty"`
+	// TOTPSecret is the secret of the authenticator app, logins require a code once the
+	// enrollment is confirmed and TOTPEnabled set. TOTPLastStep is the time step of the
+	// last code used and RecoveryCodes are the hashes of unused recovery codes
+	TOTPSecret    string   `json:"-" bson:"totp_secret,omitempty"`
+	TOTPEnabled   bool     `json:"totp_enabled" bson:"totp_enabled,omitempty"`
+	TOTPLastStep  int64    `json:"-" bson:"totp_last_step,omitempty"`
+	RecoveryCodes []string `json:"-" bson:"recovery_codes,omitempty"`
+}
+
+// IsActive return true if the member can log in, members created before statuses existed are active
+func (m Member) IsActive() bool {
+	return m.Status == "" || m.Status == MemberStatusActive
+}
+
+// GetRole return the role of the member, members created before roles existed are guests
+func (m Member) GetRole() Role {
+	if m.Role == "" {
+		return RoleGuest
+	}
+	return m.Role
 }
 
 type MemberRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Password string `json:"password" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
+	Role     Role   `json:"role" validate:"omitempty,oneof=owner manager staff guest"`
 }
 
 type UpdateMemberRequest struct {
@@ -34,15 +96,29 @@ type MemberSignUp struct {
 }
 
 type MemberFieldInToken struct {
-	ID    primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
-	Name  string             `json:"name"`
-	Email string             `json:"email"`
+	ID        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Name      string             `json:"name"`
+	Email     string             `json:"email"`
+	Role      Role               `json:"role"`
+	SessionID primitive.ObjectID `json:"sid"`
 }
 
 type MemberResponseSignUp struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Token string `json:"token"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// ChallengeToken is returned instead of tokens when the member has to send a 2FA code
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,gte=8"`
 }
 
 type MemberLogin struct {
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TokenPurpose tells what a member token can be used for
+type TokenPurpose string
+
+const (
+	TokenPurposeVerifyEmail   TokenPurpose = "verify_email"
+	TokenPurposeResetPassword TokenPurpose = "reset_password"
+	// TokenPurposeLoginChallenge is returned on login of a member with 2FA, to send with the code
+	TokenPurposeLoginChallenge TokenPurpose = "login_challenge"
+)
+
+// MemberToken hold a single-use token sent to a member by mail,
+// only the hash of the token is stored
+type MemberToken struct {
+	ID        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	MemberID  primitive.ObjectID `json:"member_id" bson:"member_id"`
+	Purpose   TokenPurpose       `json:"purpose" bson:"purpose"`
+	Hash      string             `json:"-" bson:"hash"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	Used      bool               `json:"used" bson:"used"`
+	CreateAt  time.Time          `json:"create_at" bson:"create_at"`
+}
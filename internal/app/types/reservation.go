@@ -0,0 +1,56 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reservation hold information of a booking of a table for a time slot
+type Reservation struct {
+	ID        primitive.ObjectID `json:"_id" bson:"_id,omitempty" validate:"required"`
+	TableID   primitive.ObjectID `json:"table_id" bson:"table_id" validate:"required"`
+	MemberID  primitive.ObjectID `json:"member_id" bson:"member_id,omitempty"`
+	Name      string             `json:"name" bson:"name" validate:"required"`
+	Phone     string             `json:"phone" bson:"phone"`
+	PartySize int                `json:"party_size" bson:"party_size" validate:"required,min=1"`
+	StartTime time.Time          `json:"start_time" bson:"start_time" validate:"required"`
+	EndTime   time.Time          `json:"end_time" bson:"end_time" validate:"required"`
+	Note      string             `json:"note" bson:"note"`
+	DelFlg    bool               `json:"del_flg" bson:"del_flg" validate:"omitempty"`
+	CreateAt  time.Time          `json:"create_at" bson:"create_at"`
+	UpdateAt  time.Time          `json:"update_at" bson:"update_at"`
+}
+
+type ReservationRequest struct {
+	TableID   string    `json:"table_id" validate:"required"`
+	Name      string    `json:"name" validate:"required,max=60"`
+	Phone     string    `json:"phone" validate:"omitempty,max=20"`
+	PartySize int       `json:"party_size" validate:"required,min=1"`
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+	Note      string    `json:"note" validate:"omitempty,max=255"`
+}
+
+type UpdateReservationRequest struct {
+	ID        string    `json:"_id" validate:"required"`
+	TableID   string    `json:"table_id" validate:"required"`
+	Name      string    `json:"name" validate:"required,max=60"`
+	Phone     string    `json:"phone" validate:"omitempty,max=20"`
+	PartySize int       `json:"party_size" validate:"required,min=1"`
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+	Note      string    `json:"note" validate:"omitempty,max=255"`
+}
+
+// ReservationFilter narrows down a listing of reservations,
+// zero values are ignored
+type ReservationFilter struct {
+	TableID  string
+	MemberID string
+	// TableIDs select reservations of any of the tables
+	TableIDs []string
+	// From and To select reservations overlapping [From, To)
+	From time.Time
+	To   time.Time
+}
@@ -1,13 +1,33 @@
 package types
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // restaurant hold information of a restaurant
 type Restaurant struct {
-	ID    primitive.ObjectID `json:"_id" bson:"_id,omitempty" validate:"required"`
-	Name  string             `json:"name" bson:"name" validate:"required"`
-	Address string 		     `json:"address" bson:"address" validate:"omitempty,address"`
-	// numberOfTable int 		 `json:"number_of_table bson:"number_of_table" validate:"required"`
-	// Table []TableInRestaurant ``
-	DelFlg bool 			 `json:"del_flg" bson:"del_flg" validate:"required" `
+	ID       primitive.ObjectID `json:"_id" bson:"_id,omitempty" validate:"required"`
+	Name     string             `json:"name" bson:"name" validate:"required"`
+	Address  string             `json:"address" bson:"address" validate:"omitempty"`
+	DelFlg   bool               `json:"del_flg" bson:"del_flg" validate:"omitempty"`
+	CreateAt time.Time          `json:"create_at" bson:"create_at"`
+	UpdateAt time.Time          `json:"update_at" bson:"update_at"`
+}
+
+type RestaurantRequest struct {
+	Name    string `json:"name" validate:"required,max=100"`
+	Address string `json:"address" validate:"omitempty,max=255"`
+}
+
+type UpdateRestaurantRequest struct {
+	ID      string `json:"_id" bson:"_id,omitempty" validate:"required"`
+	Name    string `json:"name" bson:"name" validate:"required,max=100"`
+	Address string `json:"address" bson:"address" validate:"omitempty,max=255"`
+}
+
+type DeleteRestaurantRequest struct {
+	ID     string `json:"_id" bson:"_id,omitempty" validate:"required"`
+	DelFlg bool   `json:"del_flg" bson:"del_flg" validate:"required"`
 }
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session hold a login of a member, its refresh token is rotated on every refresh
+// and only the hash of the current one is stored
+type Session struct {
+	ID               primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	MemberID         primitive.ObjectID `json:"member_id" bson:"member_id"`
+	RefreshTokenHash string             `json:"-" bson:"refresh_token_hash"`
+	ExpiresAt        time.Time          `json:"expires_at" bson:"expires_at"`
+	Revoked          bool               `json:"revoked" bson:"revoked"`
+	CreateAt         time.Time          `json:"create_at" bson:"create_at"`
+	UpdateAt         time.Time          `json:"update_at" bson:"update_at"`
+}
+
+// Tokens hold a short-lived access token and the refresh token to renew it
+type Tokens struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -14,10 +14,11 @@ type Table struct {
 	DelFlg 			bool 					`json:"del_flg" bson:"del_flg" validate:"omitempty"`
 	CreateAt     	time.Time         		`json:"create_at" bson:"create_at"`
 	UpdateAt     	time.Time          		`json:"update_at" bson:"update_at"`
-	// RestaurantId 	primitive.ObjectID   	`json:"restaurant_id" bson:"restaurant_id validate:"required"` 
+	RestaurantID 	primitive.ObjectID   	`json:"restaurant_id" bson:"restaurant_id" validate:"required"`
 }
 
 type TableRequest struct {
+	RestaurantID	string					`json:"restaurant_id" bson:"restaurant_id" validate:"required"`
 	Status			string					`json:"status" bson:"status" validate:"required"`
 	Type			string					`json:"type" bson:"type" validate:"required"`
 	Slots 			int 					`json:"slots" bson:"slots" validate:"required,max=10"`
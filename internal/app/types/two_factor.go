@@ -0,0 +1,29 @@
+package types
+
+// TwoFactorEnrollment hold the secret of a pending TOTP enrollment,
+// URI is the otpauth URI to scan with an authenticator app
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TwoFactorCodeRequest hold a code of the authenticator app or a recovery code
+type TwoFactorCodeRequest struct {
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	TwoFactorCodeRequest
+}
+
+// RecoveryCodes hold the recovery codes shown once when 2FA is enabled,
+// each one can replace a code of the authenticator app once
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,85 @@
+package apperr
+
+import (
+	"errors"
+)
+
+// Kind tells what went wrong, handlers answer errors with the HTTP status of their kind
+type Kind int
+
+const (
+	// Unknown errors are unexpected failures
+	Unknown Kind = iota
+	NotFound
+	Conflict
+	Unauthorized
+	Forbidden
+	Validation
+	// Unavailable errors are failures of a dependency such as the database, retrying may succeed
+	Unavailable
+	TooManyRequests
+)
+
+var kindNames = map[Kind]string{
+	Unknown:         "unknown",
+	NotFound:        "not found",
+	Conflict:        "conflict",
+	Unauthorized:    "unauthorized",
+	Forbidden:       "forbidden",
+	Validation:      "validation",
+	Unavailable:     "unavailable",
+	TooManyRequests: "too many requests",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Error is a domain error. Code is the key of the error code answered to clients in
+// errors.en.yml, e.g. database.data_not_found, the code of the kind is used when empty
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+}
+
+// New return a domain error, usually declared as a variable to be compared with errors.Is
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap return a domain error caused by err
+func Wrap(kind Kind, code string, err error) *Error {
+	return &Error{Kind: kind, Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap return the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// As return the first domain error in the chain of err
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// KindOf return the kind of the first domain error in the chain of err, Unknown if there is none
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return Unknown
+}
@@ -1,11 +1,18 @@
 package auth
 
 import (
-	"booking/internal/pkg/jwt"
+	"booking/internal/app/types"
+	"booking/internal/pkg/apperr"
+	"context"
 	"net/http"
 	"strings"
 )
 
+type contextKey struct{}
+
+// ErrForbidden is returned when the member is not allowed to perform an action
+var ErrForbidden = apperr.New(apperr.Forbidden, "invalid_value.permission_denied", "permission denied")
+
 // get token from Header
 func ExtractToken(r *http.Request) string {
 	tokenHeader := r.Header.Get("Authorization")
@@ -22,7 +29,25 @@ func ExtractToken(r *http.Request) string {
 	return tokenpath
 }
 
-func IsAuthorized(tokenpath string) (map[string]interface{}, error) {
-	claimMap, err := jwt.IsAuthorized(tokenpath)
-	return claimMap, err
+// NewContext return a copy of ctx carrying the claims of the authenticated member
+func NewContext(ctx context.Context, claims *types.Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromContext return the claims of the authenticated member stored in ctx
+func FromContext(ctx context.Context) (*types.Claims, bool) {
+	claims, ok := ctx.Value(contextKey{}).(*types.Claims)
+	return claims, ok
+}
+
+// HasRole return true if the authenticated member has at least the given role
+func HasRole(ctx context.Context, min types.Role) bool {
+	claims, ok := FromContext(ctx)
+	return ok && claims.Role.AtLeast(min)
+}
+
+// IsMember return true if the authenticated member is the given one
+func IsMember(ctx context.Context, id string) bool {
+	claims, ok := FromContext(ctx)
+	return ok && claims.ID.Hex() == id
 }
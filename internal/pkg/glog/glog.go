@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"booking/internal/pkg/trace"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -81,6 +83,15 @@ func getLevel() logrus.Level {
 	return lvl
 }
 
+// OutputFile return the path of the log file set by LOG_OUTPUT, empty when logging to os.Stdout
+func OutputFile() string {
+	out := os.Getenv("LOG_OUTPUT")
+	if strings.HasPrefix(out, filePrefix) {
+		return out[len(filePrefix):]
+	}
+	return ""
+}
+
 func getOutput() io.WriteCloser {
 	out := os.Getenv("LOG_OUTPUT")
 	if strings.HasPrefix(out, filePrefix) {
@@ -145,10 +156,15 @@ func (l *booking) Panicc(ctx context.Context, format string, v ...interface{}) {
 }
 
 func (l *booking) withContext(ctx context.Context) Logger {
+	var logger Logger = l
 	if requestID := ctx.Value("request_id"); requestID != nil {
-		return l.WithField("request_id", requestID)
+		logger = logger.WithField("request_id", requestID)
 	}
-	return l
+	if span, ok := trace.FromContext(ctx); ok {
+		sc := span.Context()
+		logger = logger.WithField("trace_id", sc.TraceID.String()).WithField("span_id", sc.SpanID.String())
+	}
+	return logger
 }
 
 // WithField return a new logger with field
@@ -24,16 +24,34 @@ func Ready() {
 	isReadyMu.Unlock()
 }
 
+// NotReady marks the service as not ready to receive traffic, e.g. during graceful shutdown.
+func NotReady() {
+	isReadyMu.Lock()
+	isReady = false
+	isReadyMu.Unlock()
+}
+
+// IsReady tells if the service is ready to receive traffic.
+func IsReady() bool {
+	isReadyMu.RLock()
+	defer isReadyMu.RUnlock()
+	return isReady
+}
+
 // Readiness returns an HTTP handler for checking Readiness state.
-// Will return 503 untill Ready() is called
-func Readiness() http.Handler {
+// Will return 503 untill Ready() is called or while a check fails
+func Readiness(cf ...CheckFunc) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		isReadyMu.RLock()
-		defer isReadyMu.RUnlock()
-		if !isReady {
+		if !IsReady() {
 			respond.Error(w, errors.New("not ready"), http.StatusServiceUnavailable)
 			return
 		}
+		for _, c := range cf {
+			if err := c(r.Context()); err != nil {
+				respond.Error(w, err, http.StatusServiceUnavailable)
+				return
+			}
+		}
 		respond.JSON(w, http.StatusOK, "OK")
 	})
 }
@@ -72,3 +72,30 @@ func TestLivenessChecks(t *testing.T) {
 		t.Errorf("Response.StatusCode = %d; expected %d;", resp.StatusCode, http.StatusServiceUnavailable)
 	}
 }
+
+func TestReadinessChecks(t *testing.T) {
+	var checkErr error
+	check := func(context.Context) error {
+		return checkErr
+	}
+	Ready()
+	ts := httptest.NewServer(Readiness(check))
+	defer ts.Close()
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Response.StatusCode = %d; expected %d;", resp.StatusCode, http.StatusOK)
+	}
+	checkErr = errors.New("database unreachable")
+	resp, err = http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Response.StatusCode = %d; expected %d;", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
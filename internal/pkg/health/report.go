@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"booking/internal/pkg/respond"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckTimeout bound the time of each check of a report
+var CheckTimeout = 5 * time.Second
+
+type (
+	// Check is a named health check
+	Check struct {
+		Name  string
+		Check CheckFunc
+	}
+
+	// Result is the outcome of a check
+	Result struct {
+		Name      string  `json:"name"`
+		Status    string  `json:"status"`
+		LatencyMS float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+	}
+
+	// Report is the outcome of all checks, Status is down when a check is down
+	Report struct {
+		Status string   `json:"status"`
+		Ready  bool     `json:"ready"`
+		Checks []Result `json:"checks"`
+	}
+)
+
+// Run all checks concurrently and return their results in order
+func Run(ctx context.Context, checks ...Check) Report {
+	report := Report{
+		Status: StatusUp,
+		Ready:  IsReady(),
+		Checks: make([]Result, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, CheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.Check(ctx)
+			result := Result{
+				Name:      c.Name,
+				Status:    StatusUp,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				result.Status = StatusDown
+				result.Error = err.Error()
+			}
+			report.Checks[i] = result
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// Handler returns an HTTP handler reporting the status and latency of every check as JSON,
+// the status code is 503 when a check is down
+func Handler(checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := Run(r.Context(), checks...)
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		respond.JSON(w, status, report)
+	})
+}
+
+// Funcs return the functions of checks
+func Funcs(checks []Check) []CheckFunc {
+	funcs := make([]CheckFunc, 0, len(checks))
+	for _, c := range checks {
+		funcs = append(funcs, c.Check)
+	}
+	return funcs
+}
+
+// Writable returns a check that a file can be written in dir, e.g. the directory of log files
+func Writable(dir string) CheckFunc {
+	return func(context.Context) error {
+		f, err := os.CreateTemp(dir, ".health-*")
+		if err != nil {
+			return err
+		}
+		name := f.Name()
+		_, err = f.Write([]byte("ok"))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		os.Remove(name)
+		return err
+	}
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	var dbErr error
+	checks := []Check{
+		{Name: "database", Check: func(context.Context) error { return dbErr }},
+		{Name: "disk", Check: Writable(t.TempDir())},
+	}
+	ts := httptest.NewServer(Handler(checks...))
+	defer ts.Close()
+
+	get := func() (int, Report) {
+		resp, err := http.Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var report Report
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, report
+	}
+
+	status, report := get()
+	if status != http.StatusOK || report.Status != StatusUp || len(report.Checks) != 2 {
+		t.Fatalf("report = %d %+v; expected 200 with 2 checks up", status, report)
+	}
+	for i, name := range []string{"database", "disk"} {
+		if c := report.Checks[i]; c.Name != name || c.Status != StatusUp || c.Error != "" || c.LatencyMS < 0 {
+			t.Errorf("check %d = %+v; expected %s up", i, c, name)
+		}
+	}
+
+	dbErr = errors.New("server selection timeout")
+	status, report = get()
+	if status != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Fatalf("report = %d %+v; expected 503 down", status, report)
+	}
+	if c := report.Checks[0]; c.Status != StatusDown || c.Error != dbErr.Error() {
+		t.Errorf("database check = %+v; expected down with error", c)
+	}
+	if c := report.Checks[1]; c.Status != StatusUp {
+		t.Errorf("disk check = %+v; expected up", c)
+	}
+}
+
+func TestWritable(t *testing.T) {
+	if err := Writable(t.TempDir())(context.Background()); err != nil {
+		t.Errorf("Writable(temp dir) = %v; expected nil", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+	if err := Writable(missing)(context.Background()); err == nil {
+		t.Error("Writable(missing dir) = nil; expected an error")
+	}
+}
+
+func TestNotReady(t *testing.T) {
+	Ready()
+	NotReady()
+	if IsReady() {
+		t.Fatal("IsReady() = true after NotReady()")
+	}
+	if report := Run(context.Background()); report.Ready || report.Status != StatusUp {
+		t.Errorf("report = %+v; expected up and not ready", report)
+	}
+}
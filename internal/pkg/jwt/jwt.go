@@ -1,10 +1,7 @@
 package jwt
 
 import (
-	//"booking/internal/app/api/types"
 	"booking/internal/app/types"
-	"encoding/base64"
-	"fmt"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -12,40 +9,40 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var (
-	jwtKey, _ = base64.URLEncoding.DecodeString("dating21")
-)
-
-// Generate token for login or sign up
-func GenToken(member types.MemberFieldInToken, duration time.Duration) (string, error) {
+// Generate token for login or sign up, signed with the signing key of the set
+func (ks *KeySet) GenToken(member types.MemberFieldInToken, duration time.Duration) (string, error) {
 	claims := &types.Claims{
-		ID:    member.ID,
-		Email: member.Email,
-		Name:  member.Name,
+		ID:        member.ID,
+		Email:     member.Email,
+		Name:      member.Name,
+		Role:      member.Role,
+		SessionID: member.SessionID,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(duration).Unix(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtKey)
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.sign)
 }
-func IsAuthorized(tokenpath string) (map[string]interface{}, error) {
 
-	token, err := jwt.Parse(tokenpath, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+// IsAuthorized verify the token with the key named by its kid header and return its claims
+func (ks *KeySet) IsAuthorized(tokenpath string) (*types.Claims, error) {
+
+	claims := &types.Claims{}
+	token, err := jwt.ParseWithClaims(tokenpath, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok || token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("Can't authorized token")
 		}
-		return jwtKey, nil
+		return key.verify, nil
 	})
 
-	if err != nil {
+	if err != nil || !token.Valid {
 		return nil, errors.New("Can't authorized token")
 	}
-
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
-	}
-	return nil, errors.New("Can't authorized token")
+	return claims, nil
 }
 
 // method hash password
@@ -60,9 +57,6 @@ func HashPassword(password string) (string, error) {
 
 // method compare Hash and Password
 func IsCorrectPassword(password, hashedPasswordStr string) bool {
-	fmt.Println("password ", []byte(hashedPasswordStr))
-	fmt.Println("hashedPasswordStr ", []byte(password))
-	err := bcrypt.CompareHashAndPassword([]byte(string(hashedPasswordStr)), []byte(string(password)))
-	fmt.Println("======CompareHashAndPassword======", err)
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPasswordStr), []byte(password))
 	return err == nil
 }
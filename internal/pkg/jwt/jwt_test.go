@@ -0,0 +1,166 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func writePEM(t *testing.T, name, typ string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testKeys(t *testing.T) []configs.JwtKey {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []configs.JwtKey{
+		{ID: "hmac", Algorithm: "HS256", Secret: "secret"},
+		{ID: "rsa", Algorithm: "RS256", PrivateKeyFile: writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))},
+		{ID: "rsa-public", Algorithm: "RS256", PublicKeyFile: writePEM(t, "rsa.pub", "PUBLIC KEY", rsaPublicDER)},
+		{ID: "ec", Algorithm: "ES256", PrivateKeyFile: writePEM(t, "ec.pem", "EC PRIVATE KEY", ecDER)},
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	keys := testKeys(t)
+	member := types.MemberFieldInToken{ID: primitive.NewObjectID(), Email: "owner@booking.local", Role: types.RoleOwner, SessionID: primitive.NewObjectID()}
+
+	for _, signing := range []string{"hmac", "rsa", "ec"} {
+		t.Run(signing, func(t *testing.T) {
+			ks, err := NewKeySet(configs.Jwt{SigningKey: signing, Keys: keys})
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := ks.GenToken(member, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := ks.IsAuthorized(token)
+			if err != nil {
+				t.Fatalf("IsAuthorized() error = %v", err)
+			}
+			if claims.ID != member.ID || claims.Role != member.Role || claims.SessionID != member.SessionID {
+				t.Errorf("IsAuthorized() claims = %+v; expected %+v", claims, member)
+			}
+
+			expired, err := ks.GenToken(member, -time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ks.IsAuthorized(expired); err == nil {
+				t.Errorf("IsAuthorized() accepted an expired token")
+			}
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	keys := testKeys(t)
+	member := types.MemberFieldInToken{ID: primitive.NewObjectID()}
+
+	old, err := NewKeySet(configs.Jwt{SigningKey: "hmac", Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := old.GenToken(member, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := NewKeySet(configs.Jwt{SigningKey: "rsa", Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rotated.IsAuthorized(token); err != nil {
+		t.Errorf("IsAuthorized() of token signed by retired key error = %v", err)
+	}
+
+	retired, err := NewKeySet(configs.Jwt{SigningKey: "rsa", Keys: keys[1:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := retired.IsAuthorized(token); err == nil {
+		t.Errorf("IsAuthorized() accepted a token signed by a removed key")
+	}
+}
+
+func TestNewKeySetErrors(t *testing.T) {
+	keys := testKeys(t)
+	tests := []struct {
+		name string
+		conf configs.Jwt
+	}{
+		{"unknown signing key", configs.Jwt{SigningKey: "missing", Keys: keys}},
+		{"signing key without private key", configs.Jwt{SigningKey: "rsa-public", Keys: keys}},
+		{"unsupported algorithm", configs.Jwt{SigningKey: "none", Keys: []configs.JwtKey{{ID: "none", Algorithm: "none", Secret: "x"}}}},
+		{"missing secret", configs.Jwt{SigningKey: "hmac", Keys: []configs.JwtKey{{ID: "hmac", Algorithm: "HS256"}}}},
+		{"key type mismatch", configs.Jwt{SigningKey: "ec", Keys: []configs.JwtKey{{ID: "ec", Algorithm: "RS256", PrivateKeyFile: keys[3].PrivateKeyFile}}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewKeySet(tc.conf); err == nil {
+				t.Errorf("NewKeySet() should fail")
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	ks, err := NewKeySet(configs.Jwt{SigningKey: "hmac", Keys: testKeys(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]JSONWebKey{}
+	var kids []string
+	for _, k := range ks.JWKS().Keys {
+		got[k.Kid] = k
+		kids = append(kids, k.Kid)
+	}
+	if expected := []string{"ec", "rsa", "rsa-public"}; !reflect.DeepEqual(kids, expected) {
+		t.Errorf("JWKS() kids = %v; expected %v", kids, expected)
+	}
+	if _, ok := got["hmac"]; ok {
+		t.Errorf("JWKS() published a symmetric key")
+	}
+	if k := got["rsa"]; k.Kty != "RSA" || k.Alg != "RS256" || k.N == "" || k.E != "AQAB" {
+		t.Errorf("JWKS() rsa key = %+v", k)
+	}
+	if k := got["ec"]; k.Kty != "EC" || k.Crv != "P-256" || len(k.X) != 43 || len(k.Y) != 43 {
+		t.Errorf("JWKS() ec key = %+v", k)
+	}
+	if len(got) != 3 {
+		t.Errorf("JWKS() returned %d keys; expected 3", len(got))
+	}
+}
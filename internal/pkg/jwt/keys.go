@@ -0,0 +1,219 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+
+	"booking/configs"
+	"booking/internal/pkg/respond"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+type (
+	// Key is a key identified by its kid, keys without signing material can only verify tokens
+	Key struct {
+		ID     string
+		Method jwt.SigningMethod
+		sign   interface{}
+		verify interface{}
+	}
+
+	// KeySet hold the key signing new tokens and every key accepted to verify tokens,
+	// keeping retired keys in the set lets tokens they signed live until they expire
+	KeySet struct {
+		signing *Key
+		keys    map[string]*Key
+	}
+
+	// JSONWebKey is the public part of an asymmetric key as defined by RFC 7517
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	// JSONWebKeySet is the document served at /.well-known/jwks.json
+	JSONWebKeySet struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+)
+
+// NewKeySet load the keys from configuration, the signing key must hold signing material
+func NewKeySet(conf configs.Jwt) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]*Key{}}
+	for _, kc := range conf.Keys {
+		key, err := loadKey(kc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load jwt key %q", kc.ID)
+		}
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, errors.Errorf("duplicated jwt key %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	signing, ok := ks.keys[conf.SigningKey]
+	if !ok {
+		return nil, errors.Errorf("jwt signing key %q is not configured", conf.SigningKey)
+	}
+	if signing.sign == nil {
+		return nil, errors.Errorf("jwt signing key %q has no private key", conf.SigningKey)
+	}
+	ks.signing = signing
+	return ks, nil
+}
+
+func loadKey(kc configs.JwtKey) (*Key, error) {
+	if kc.ID == "" {
+		return nil, errors.New("missing key id")
+	}
+	method := jwt.GetSigningMethod(kc.Algorithm)
+	if method == nil {
+		return nil, errors.Errorf("unsupported algorithm %q", kc.Algorithm)
+	}
+	key := &Key{ID: kc.ID, Method: method}
+
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		secret := kc.Secret
+		if kc.SecretEnv != "" && os.Getenv(kc.SecretEnv) != "" {
+			secret = os.Getenv(kc.SecretEnv)
+		}
+		if secret == "" {
+			return nil, errors.New("missing secret")
+		}
+		key.sign, key.verify = []byte(secret), []byte(secret)
+		return key, nil
+	}
+
+	if kc.PrivateKeyFile != "" {
+		private, err := readPrivateKey(kc.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.sign, key.verify = private, private.Public()
+	}
+	if kc.PublicKeyFile != "" {
+		public, err := readPublicKey(kc.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key.verify = public
+	}
+	if key.verify == nil {
+		return nil, errors.New("missing private or public key file")
+	}
+
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, ok := key.verify.(*rsa.PublicKey); !ok {
+			return nil, errors.Errorf("%s requires a RSA key", kc.Algorithm)
+		}
+	case *jwt.SigningMethodECDSA:
+		if _, ok := key.verify.(*ecdsa.PublicKey); !ok {
+			return nil, errors.Errorf("%s requires an ECDSA key", kc.Algorithm)
+		}
+	default:
+		return nil, errors.Errorf("unsupported algorithm %q", kc.Algorithm)
+	}
+	return key, nil
+}
+
+// readPrivateKey read a PEM encoded PKCS #8, PKCS #1 (RSA) or SEC 1 (EC) private key
+func readPrivateKey(name string) (crypto.Signer, error) {
+	block, err := readPEM(name)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.Errorf("unsupported private key in %s", name)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.Errorf("unsupported private key in %s", name)
+}
+
+// readPublicKey read a PEM encoded PKIX public key
+func readPublicKey(name string) (crypto.PublicKey, error) {
+	block, err := readPEM(name)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func readPEM(name string) (*pem.Block, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data found in %s", name)
+	}
+	return block, nil
+}
+
+// JWKS return the public keys of the set ordered by kid, symmetric keys are never published
+func (ks *KeySet) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range ks.keys {
+		jwk := JSONWebKey{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encode(public.N.Bytes())
+			jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (public.Curve.Params().BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = public.Curve.Params().Name
+			jwk.X = encode(public.X.FillBytes(make([]byte, size)))
+			jwk.Y = encode(public.Y.FillBytes(make([]byte, size)))
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	// keys are kept in a map, sort them so the document is the same on every request
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set
+}
+
+// Handler return an HTTP handler serving the JSON Web Key Set
+func (ks *KeySet) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		respond.JSON(w, http.StatusOK, ks.JWKS())
+	})
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
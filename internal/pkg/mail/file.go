@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// File write every message as an .eml file into a directory, useful for local development
+type File struct {
+	from string
+	dir  string
+}
+
+// NewFile return a new file sender, the directory is created if missing
+func NewFile(from, dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &File{from: from, dir: dir}, nil
+}
+
+// Send write the message to a new file
+func (f *File) Send(ctx context.Context, msg Message) error {
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(msg.To))
+	return ioutil.WriteFile(filepath.Join(f.dir, name), format(f.from, msg), 0600)
+}
@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+
+	"booking/configs"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeSMTP   = "smtp"
+	TypeFile   = "file"
+	TypeMemory = "memory"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender is an interface of mail delivery
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New return the sender selected by the mail configuration
+func New(conf configs.Mail) (Sender, error) {
+	switch conf.Type {
+	case TypeSMTP:
+		return NewSMTP(conf), nil
+	case TypeFile:
+		return NewFile(conf.From, conf.FileDir)
+	case TypeMemory:
+		return NewMemory(), nil
+	}
+	return nil, errors.Errorf("mail type not supported: %s", conf.Type)
+}
@@ -0,0 +1,32 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory keep sent messages in memory, useful for tests
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemory return a new memory sender
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Send record the message
+func (m *Memory) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages return the messages sent so far
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
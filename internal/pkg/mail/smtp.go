@@ -0,0 +1,56 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"booking/configs"
+)
+
+// SMTP deliver messages through an SMTP server
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTP return a new SMTP sender, PLAIN authentication is used when a username is configured
+func NewSMTP(conf configs.Mail) *SMTP {
+	s := &SMTP{
+		addr: fmt.Sprintf("%s:%d", conf.SMTP.Host, conf.SMTP.Port),
+		from: conf.From,
+	}
+	if conf.SMTP.Username != "" {
+		s.auth = smtp.PlainAuth("", conf.SMTP.Username, conf.SMTP.Password, conf.SMTP.Host)
+	}
+	return s
+}
+
+// Send deliver the message
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	return smtp.SendMail(s.addr, s.auth, envelope(s.from), []string{msg.To}, format(s.from, msg))
+}
+
+// envelope return the bare address of "Name <address>"
+func envelope(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+// format return the message in RFC 5322 format
+func format(from string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,28 @@
+package metrics
+
+// Metrics of the booking service
+var (
+	HTTPRequests = NewCounter("booking_http_requests_total",
+		"HTTP requests served by route template, method and status code.",
+		"route", "method", "status")
+	HTTPRequestDuration = NewHistogram("booking_http_request_duration_seconds",
+		"Latency of HTTP requests by route template and method.",
+		DefaultBuckets, "route", "method")
+
+	DatabaseOperationDuration = NewHistogram("booking_database_operation_duration_seconds",
+		"Latency of MongoDB commands by command, collection and result.",
+		DefaultBuckets, "operation", "collection", "result")
+
+	RateLimited = NewCounter("booking_rate_limited_requests_total",
+		"Requests rejected by a rate limit policy.",
+		"policy")
+
+	Logins = NewCounter("booking_logins_total",
+		"Successful logins of members.")
+	FailedLogins = NewCounter("booking_failed_logins_total",
+		"Failed logins because of an unknown email, a wrong password or a wrong two-factor code.")
+	TablesCreated = NewCounter("booking_tables_created_total",
+		"Tables created.")
+	ReservationsCreated = NewCounter("booking_reservations_created_total",
+		"Reservations made.")
+)
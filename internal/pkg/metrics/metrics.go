@@ -0,0 +1,242 @@
+// Package metrics hold counters and histograms exposed in the Prometheus text format
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds in seconds of latency histograms
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type (
+	// Registry hold metrics in order of registration
+	Registry struct {
+		mu      sync.Mutex
+		metrics []metric
+		names   map[string]bool
+	}
+
+	metric interface {
+		write(w io.Writer)
+	}
+
+	// desc describe a metric and hold its series by label values
+	desc struct {
+		name   string
+		help   string
+		labels []string
+		mu     sync.Mutex
+		series map[string]*series
+	}
+
+	series struct {
+		values  []string
+		value   float64
+		buckets []uint64
+		count   uint64
+	}
+
+	// Counter is a metric that only goes up, e.g. requests served
+	Counter struct {
+		desc
+	}
+
+	// Histogram count observations, e.g. latencies, in buckets of upper bounds
+	Histogram struct {
+		desc
+		buckets []float64
+	}
+)
+
+// Default is the registry exposed by Handler
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		names: map[string]bool{},
+	}
+}
+
+// NewCounter register a counter in the default registry
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.Counter(name, help, labels...)
+}
+
+// NewHistogram register a histogram in the default registry
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.Histogram(name, help, buckets, labels...)
+}
+
+// Counter register a counter with given label names, it panics if the name is taken
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: newDesc(name, help, labels)}
+	r.register(name, c)
+	return c
+}
+
+// Histogram register a histogram with given buckets and label names, it panics if the name is taken
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &Histogram{desc: newDesc(name, help, labels), buckets: sorted}
+	r.register(name, h)
+	return h
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metric %s registered twice", name))
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// Write all metrics in the Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler returns an HTTP handler exposing the metrics of the registry
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// Handler returns an HTTP handler exposing the metrics of the default registry
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+func newDesc(name, help string, labels []string) desc {
+	return desc{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: map[string]*series{},
+	}
+}
+
+// get return the series of label values, the caller must hold the lock
+func (d *desc) get(values []string, buckets int) *series {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric %s has labels %v, got values %v", d.name, d.labels, values))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := d.series[key]
+	if !ok {
+		s = &series{values: append([]string(nil), values...), buckets: make([]uint64, buckets)}
+		d.series[key] = s
+	}
+	return s
+}
+
+// sorted return a copy of the series ordered by label values
+func (d *desc) sorted() []series {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := make([]string, 0, len(d.series))
+	for key := range d.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	all := make([]series, 0, len(keys))
+	for _, key := range keys {
+		s := *d.series[key]
+		s.buckets = append([]uint64(nil), s.buckets...)
+		all = append(all, s)
+	}
+	return all
+}
+
+func (d *desc) header(w io.Writer, kind string) {
+	help := strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(d.help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, help, d.name, kind)
+}
+
+// labelPairs format the labels of a sample, extra is appended as is
+func (d *desc) labelPairs(values []string, extra string) string {
+	pairs := make([]string, 0, len(values)+1)
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	for i, label := range d.labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label, escape.Replace(values[i])))
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Inc add 1 to the series of given label values
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add v, which must not be negative, to the series of given label values
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("counter %s can't decrease", c.name))
+	}
+	c.mu.Lock()
+	c.get(values, 0).value += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.header(w, "counter")
+	for _, s := range c.sorted() {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(s.values, ""), formatFloat(s.value))
+	}
+}
+
+// Observe add an observation to the series of given label values
+func (h *Histogram) Observe(v float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.get(values, len(h.buckets))
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.buckets[i]++
+		}
+	}
+	s.value += v
+	s.count++
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.header(w, "histogram")
+	for _, s := range h.sorted() {
+		for i, upper := range h.buckets {
+			le := fmt.Sprintf(`le="%s"`, formatFloat(upper))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, le), s.buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(s.values, `le="+Inf"`), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(s.values, ""), formatFloat(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(s.values, ""), s.count)
+	}
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	r := NewRegistry()
+	requests := r.Counter("requests_total", "Requests\nserved.", "route", "status")
+	latency := r.Histogram("latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+	logins := r.Counter("logins_total", "Logins.")
+
+	requests.Inc("/member/{id}", "200")
+	requests.Add(2, "/member/{id}", "200")
+	requests.Inc(`/a"b`, "404")
+	latency.Observe(0.05, "/member/{id}")
+	latency.Observe(0.5, "/member/{id}")
+	latency.Observe(3, "/member/{id}")
+
+	var b strings.Builder
+	r.Write(&b)
+	want := `# HELP requests_total Requests\nserved.
+# TYPE requests_total counter
+requests_total{route="/a\"b",status="404"} 1
+requests_total{route="/member/{id}",status="200"} 3
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/member/{id}",le="0.1"} 1
+latency_seconds_bucket{route="/member/{id}",le="1"} 2
+latency_seconds_bucket{route="/member/{id}",le="+Inf"} 3
+latency_seconds_sum{route="/member/{id}"} 3.55
+latency_seconds_count{route="/member/{id}"} 3
+# HELP logins_total Logins.
+# TYPE logins_total counter
+`
+	if b.String() != want {
+		t.Errorf("exposition =\n%s\nexpected\n%s", b.String(), want)
+	}
+
+	logins.Inc()
+	b.Reset()
+	r.Write(&b)
+	if !strings.HasSuffix(b.String(), "logins_total 1\n") {
+		t.Errorf("exposition =\n%s\nexpected logins_total 1 last", b.String())
+	}
+}
+
+func TestRegistryPanics(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("total", "Total.", "route")
+	for name, f := range map[string]func(){
+		"duplicate name":  func() { r.Counter("total", "Total.") },
+		"missing label":   func() { c.Inc() },
+		"negative amount": func() { c.Add(-1, "/") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("total", "Total.").Inc()
+	ts := httptest.NewServer(r.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q; expected the text exposition format", ct)
+	}
+	if !strings.Contains(string(body), "total 1\n") {
+		t.Errorf("body = %q; expected total 1", body)
+	}
+}
@@ -1,32 +1,75 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
 	"booking/configs"
+	"booking/internal/app/types"
 	"booking/internal/pkg/auth"
 	"booking/internal/pkg/glog"
 	"booking/internal/pkg/respond"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func Auth(h http.HandlerFunc, em *configs.ErrorMessage) http.HandlerFunc {
-	logger := glog.New().WithField("package", "middleware")
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tokenpath := auth.ExtractToken(r)
-		if tokenpath == "" {
-			logger.Infof("The request does not contain token")
-			respond.JSON(w, http.StatusUnauthorized, &em.InvalidValue.FailedAuthentication)
-			return
-		}
-		_, err := auth.IsAuthorized(tokenpath)
-
-		if err != nil {
-			logger.Errorf("Not authorized, error: ", err)
-			respond.JSON(w, http.StatusUnauthorized, &em.InvalidValue.FailedAuthentication)
-			return
-		}
-
-		h.ServeHTTP(w, r)
-	})
+// TokenVerifier verify a token and return its claims
+type TokenVerifier interface {
+	IsAuthorized(tokenpath string) (*types.Claims, error)
+}
+
+// SessionChecker tells whether the session a token was issued for is still active
+type SessionChecker interface {
+	IsActive(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+// Auth return a middleware that only let requests with a valid token
+// of an active session through, the claims of the token are put in the request context
+func Auth(verifier TokenVerifier, sessions SessionChecker) func(http.HandlerFunc, *configs.ErrorMessage) http.HandlerFunc {
+	return func(h http.HandlerFunc, em *configs.ErrorMessage) http.HandlerFunc {
+		logger := glog.New().WithField("package", "middleware")
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenpath := auth.ExtractToken(r)
+			if tokenpath == "" {
+				logger.Infof("The request does not contain token")
+				respond.JSON(w, http.StatusUnauthorized, &respond.Lang(r, em).InvalidValue.FailedAuthentication)
+				return
+			}
+			claims, err := verifier.IsAuthorized(tokenpath)
+
+			if err != nil {
+				logger.Errorf("Not authorized, error: %v", err)
+				respond.JSON(w, http.StatusUnauthorized, &respond.Lang(r, em).InvalidValue.FailedAuthentication)
+				return
+			}
+
+			active, err := sessions.IsActive(r.Context(), claims.SessionID)
+			if err != nil || !active {
+				logger.Infoc(r.Context(), "Session %v is not active, error: %v", claims.SessionID.Hex(), err)
+				respond.JSON(w, http.StatusUnauthorized, &respond.Lang(r, em).InvalidValue.FailedAuthentication)
+				return
+			}
+
+			h.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), claims)))
+		})
+	}
+}
+
+// Role return a middleware that only let members having at least the given role through,
+// it must run after Auth
+func Role(min types.Role) func(http.HandlerFunc, *configs.ErrorMessage) http.HandlerFunc {
+	return func(h http.HandlerFunc, em *configs.ErrorMessage) http.HandlerFunc {
+		logger := glog.New().WithField("package", "middleware")
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auth.HasRole(r.Context(), min) {
+				logger.Infoc(r.Context(), "Member is not allowed, required role: %v", min)
+				respond.JSON(w, http.StatusForbidden, &respond.Lang(r, em).InvalidValue.PermissionDenied)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
 }
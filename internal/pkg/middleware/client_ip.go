@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"booking/internal/pkg/utils"
+)
+
+// ClientIP return a middleware that resolve the IP address of the client from the forwarded
+// headers of the trusted proxies and keep it in the context for utils.ClientIP
+func ClientIP(proxies utils.TrustedProxies) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(utils.WithClientIP(r.Context(), proxies.ClientIP(r))))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"booking/configs"
+)
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+	defaultCORSHeaders = []string{"Accept", "Accept-Language", "Authorization", "Content-Type", RequestIDHeader}
+)
+
+type cors struct {
+	origins     []string
+	methods     []string
+	headers     map[string]bool
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+// CORS return a middleware answering preflight requests and allowing the configured origins to
+// read responses, it must wrap the router so preflight requests reach it. Requests of other
+// origins get no CORS headers and are blocked by browsers. Nothing is allowed without origins.
+func CORS(conf configs.CORS) (func(http.Handler) http.Handler, error) {
+	c := &cors{
+		origins:     conf.AllowedOrigins,
+		methods:     conf.AllowedMethods,
+		headers:     map[string]bool{},
+		exposed:     strings.Join(conf.ExposedHeaders, ", "),
+		credentials: conf.AllowCredentials,
+	}
+	for _, o := range c.origins {
+		if o == "*" && c.credentials {
+			return nil, fmt.Errorf("cors: credentials can't be allowed to any origin")
+		}
+	}
+	if len(c.methods) == 0 {
+		c.methods = defaultCORSMethods
+	}
+	headers := conf.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	for _, h := range headers {
+		c.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	if conf.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(conf.MaxAge.Seconds()))
+	}
+
+	return func(h http.Handler) http.Handler {
+		if len(c.origins) == 0 {
+			return h
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.serve(h, w, r)
+		})
+	}, nil
+}
+
+func (c *cors) serve(h http.Handler, w http.ResponseWriter, r *http.Request) {
+	// responses depend on the origin, caches must not share them between origins
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !c.originAllowed(origin) {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	if !preflight {
+		c.allowOrigin(w, origin)
+		if c.exposed != "" {
+			w.Header().Set("Access-Control-Expose-Headers", c.exposed)
+		}
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+	if !c.methodAllowed(r.Header.Get("Access-Control-Request-Method")) || !c.headersAllowed(r.Header.Get("Access-Control-Request-Headers")) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	c.allowOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.methods, ", "))
+	if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+		w.Header().Set("Access-Control-Allow-Headers", requested)
+	}
+	if c.maxAge != "" {
+		w.Header().Set("Access-Control-Max-Age", c.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) allowOrigin(w http.ResponseWriter, origin string) {
+	if c.credentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	for _, o := range c.origins {
+		if o == "*" {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			return
+		}
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+}
+
+func (c *cors) originAllowed(origin string) bool {
+	for _, o := range c.origins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+		// https://*.booking.com matches subdomains, not booking.com itself
+		if i := strings.Index(o, "*."); i >= 0 {
+			prefix, suffix := o[:i], o[i+1:]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *cors) methodAllowed(method string) bool {
+	for _, m := range c.methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cors) headersAllowed(requested string) bool {
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		if h != "" && !c.headers[http.CanonicalHeaderKey(h)] {
+			return false
+		}
+	}
+	return true
+}
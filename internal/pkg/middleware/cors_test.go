@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"booking/configs"
+)
+
+func TestCORS(t *testing.T) {
+	mw, err := CORS(configs.CORS{
+		AllowedOrigins:   []string{"https://widget.booking.local", "https://*.example.com"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		status  int
+		want    map[string]string
+	}{
+		{
+			name:   "same origin",
+			method: http.MethodGet,
+			status: http.StatusTeapot,
+			want:   map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:    "allowed origin",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://widget.booking.local"},
+			status:  http.StatusTeapot,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://widget.booking.local",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+			},
+		},
+		{
+			name:    "subdomain",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://a.example.com"},
+			status:  http.StatusTeapot,
+			want:    map[string]string{"Access-Control-Allow-Origin": "https://a.example.com"},
+		},
+		{
+			name:    "other origin",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://example.com"},
+			status:  http.StatusTeapot,
+			want:    map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "preflight",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://widget.booking.local",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "authorization, content-type",
+			},
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":  "https://widget.booking.local",
+				"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE",
+				"Access-Control-Allow-Headers": "authorization, content-type",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:   "preflight of a header not allowed",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://widget.booking.local",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Other",
+			},
+			status: http.StatusForbidden,
+			want:   map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "preflight of a method not allowed",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://widget.booking.local",
+				"Access-Control-Request-Method": "PATCH",
+			},
+			status: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/api/v1/table", nil)
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		for k, v := range tt.want {
+			if got := w.Header().Get(k); got != v {
+				t.Errorf("%s: %s = %q, want %q", tt.name, k, got, v)
+			}
+		}
+		if w.Header().Get("Vary") != "Origin" {
+			t.Errorf("%s: Vary = %v, want Origin first", tt.name, w.Header()["Vary"])
+		}
+	}
+
+	if _, err := CORS(configs.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}); err == nil {
+		t.Error("CORS() with credentials for any origin error = nil")
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	conf := configs.SecurityHeaders{ContentTypeNosniff: true, FrameOptions: "DENY"}
+	conf.HSTS.MaxAge = 365 * 24 * time.Hour
+	conf.HSTS.IncludeSubdomains = true
+	h := SecurityHeaders(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	want := map[string]string{
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Content-Security-Policy":   "",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"booking/internal/pkg/metrics"
+
+	"github.com/gorilla/mux"
+)
+
+// Metrics is a handler that count requests and observe their latency by route template,
+// so /api/v1/member/{id} is a single series whatever the id
+func Metrics(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bg := time.Now()
+		inner.ServeHTTP(w, r)
+
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		code := http.StatusOK
+		if mw, ok := w.(interface{ Status() int }); ok {
+			code = mw.Status()
+		}
+		metrics.HTTPRequests.Inc(route, r.Method, strconv.Itoa(code))
+		metrics.HTTPRequestDuration.Observe(time.Since(bg).Seconds(), route, r.Method)
+	})
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"booking/configs"
+	"booking/internal/pkg/auth"
+	"booking/internal/pkg/glog"
+	"booking/internal/pkg/metrics"
+	"booking/internal/pkg/ratelimit"
+	"booking/internal/pkg/respond"
+	"booking/internal/pkg/utils"
+)
+
+// RateLimit return a middleware limiting requests with a policy of the limiter. It runs before
+// Auth so requests failing authentication are limited too, policies keyed by member read the
+// member of tokens the verifier accepts and count requests without a valid token by IP.
+func RateLimit(limiter *ratelimit.Limiter, policy ratelimit.Policy, verifier TokenVerifier) func(http.HandlerFunc, *configs.ErrorMessage) http.HandlerFunc {
+	return func(h http.HandlerFunc, em *configs.ErrorMessage) http.HandlerFunc {
+		logger := glog.New().WithField("package", "middleware")
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := limiter.Take(r.Context(), policy, rateLimitKey(limiter, policy, verifier, r))
+			if err != nil {
+				// let requests through rather than failing them when the store is down
+				logger.Errorc(r.Context(), "failed to take rate limit token, policy: %s, err: %v", policy.Name, err)
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprint(res.Limit))
+			w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(ceilSeconds(res.Reset)))
+			if !res.Allowed {
+				metrics.RateLimited.Inc(policy.Name)
+				logger.Infoc(r.Context(), "Rate limited, policy: %s", policy.Name)
+				w.Header().Set("Retry-After", fmt.Sprint(ceilSeconds(res.RetryAfter)))
+				respond.JSON(w, http.StatusTooManyRequests, respond.Lang(r, em).TooManyRequests.RateLimited)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey return what the request is counted by, the client IP when it has no valid token or known key
+func rateLimitKey(limiter *ratelimit.Limiter, policy ratelimit.Policy, verifier TokenVerifier, r *http.Request) string {
+	switch policy.Key {
+	case ratelimit.KeyMember:
+		if claims, ok := auth.FromContext(r.Context()); ok {
+			return "member:" + claims.ID.Hex()
+		}
+		if token := auth.ExtractToken(r); token != "" && verifier != nil {
+			if claims, err := verifier.IsAuthorized(token); err == nil {
+				return "member:" + claims.ID.Hex()
+			}
+		}
+	case ratelimit.KeyAPIKey:
+		// unknown keys share the bucket of the IP so clients can't get a bucket per request
+		if digest, ok := limiter.APIKey(r.Header.Get(limiter.APIKeyHeader())); ok {
+			return "api_key:" + digest
+		}
+	}
+	return "ip:" + utils.ClientIP(r)
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"booking/configs"
+	"booking/internal/app/types"
+	"booking/internal/pkg/ratelimit"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type tokens map[string]primitive.ObjectID
+
+func (t tokens) IsAuthorized(token string) (*types.Claims, error) {
+	id, ok := t[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &types.Claims{ID: id}, nil
+}
+
+func TestRateLimitBeforeAuth(t *testing.T) {
+	limiter, err := ratelimit.New(configs.RateLimit{Policies: map[string]configs.RateLimitPolicy{
+		"api": {Key: ratelimit.KeyMember, Limit: 1, Period: time.Hour},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, _ := limiter.Policy("api")
+	verifier := tokens{"alice": primitive.NewObjectID(), "bob": primitive.NewObjectID()}
+	// the handler stands for Auth refusing every request
+	unauthorized := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	h := RateLimit(limiter, policy, verifier)(unauthorized, &configs.ErrorMessage{})
+
+	tests := []struct {
+		name   string
+		ip     string
+		token  string
+		status int
+	}{
+		{"no token", "203.0.113.1", "", http.StatusUnauthorized},
+		{"no token again", "203.0.113.1", "", http.StatusTooManyRequests},
+		{"invalid token counted by ip", "203.0.113.1", "forged", http.StatusTooManyRequests},
+		{"another ip", "203.0.113.2", "forged", http.StatusUnauthorized},
+		{"member", "203.0.113.1", "alice", http.StatusUnauthorized},
+		{"member again", "203.0.113.2", "alice", http.StatusTooManyRequests},
+		{"another member", "203.0.113.1", "bob", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/restaurant", nil)
+		r.RemoteAddr = tt.ip + ":5123"
+		if tt.token != "" {
+			r.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d; expected %d", tt.name, w.Code, tt.status)
+		}
+	}
+}
+
+func TestRateLimitAPIKey(t *testing.T) {
+	sum := sha256.Sum256([]byte("partner"))
+	limiter, err := ratelimit.New(configs.RateLimit{
+		APIKeys:  []string{hex.EncodeToString(sum[:])},
+		Policies: map[string]configs.RateLimitPolicy{"partner": {Key: ratelimit.KeyAPIKey, Limit: 1, Period: time.Hour}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy, _ := limiter.Policy("partner")
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	h := RateLimit(limiter, policy, nil)(ok, &configs.ErrorMessage{})
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"random key", "random-1", http.StatusOK},
+		{"another random key counted by ip", "random-2", http.StatusTooManyRequests},
+		{"no key counted by ip", "", http.StatusTooManyRequests},
+		{"known key", "partner", http.StatusOK},
+		{"known key again", "partner", http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/restaurant", nil)
+		r.RemoteAddr = "203.0.113.1:5123"
+		if tt.key != "" {
+			r.Header.Set("X-API-Key", tt.key)
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d; expected %d", tt.name, w.Code, tt.status)
+		}
+	}
+}
@@ -7,10 +7,33 @@ import (
 	"booking/internal/pkg/uuid"
 )
 
+// RequestIDHeader carry the id of a request across services
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID keep the X-Request-ID of the request, or a new one if it has none or an invalid one,
+// in the context for logs and send it back in the response
 func RequestID(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.WithValue(r.Context(), "request_id", uuid.New())
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), "request_id", id)
 		r = r.WithContext(ctx)
 		h.ServeHTTP(w, r)
 	})
 }
+
+// validRequestID accept ids of up to 128 printable ASCII characters so they are safe to log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"booking/configs"
+)
+
+// SecurityHeaders return a middleware setting the configured security headers on every response
+func SecurityHeaders(conf configs.SecurityHeaders) func(http.Handler) http.Handler {
+	headers := map[string]string{}
+	if conf.HSTS.MaxAge > 0 {
+		hsts := fmt.Sprintf("max-age=%d", int(conf.HSTS.MaxAge.Seconds()))
+		if conf.HSTS.IncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if conf.HSTS.Preload {
+			hsts += "; preload"
+		}
+		headers["Strict-Transport-Security"] = hsts
+	}
+	if conf.ContentTypeNosniff {
+		headers["X-Content-Type-Options"] = "nosniff"
+	}
+	if conf.FrameOptions != "" {
+		headers["X-Frame-Options"] = conf.FrameOptions
+	}
+	if conf.ContentSecurityPolicy != "" {
+		headers["Content-Security-Policy"] = conf.ContentSecurityPolicy
+	}
+	if conf.ReferrerPolicy != "" {
+		headers["Referrer-Policy"] = conf.ReferrerPolicy
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"booking/internal/pkg/trace"
+
+	"github.com/gorilla/mux"
+)
+
+// TraceparentHeader carry the W3C trace context of a request across services
+const TraceparentHeader = "traceparent"
+
+// Tracing is a handler that record a server span of the request, child of the span of its
+// traceparent header if any, and send the traceparent of the span back in the response
+func Tracing(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if remote, ok := trace.ParseTraceparent(r.Header.Get(TraceparentHeader)); ok {
+			ctx = trace.WithRemote(ctx, remote)
+		}
+
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tpl, err := current.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		ctx, span := trace.StartKind(ctx, fmt.Sprintf("HTTP %s %s", r.Method, route), trace.KindServer)
+		defer span.End()
+		span.SetAttribute("http.method", r.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("http.target", r.URL.Path)
+		if id, ok := ctx.Value("request_id").(string); ok {
+			span.SetAttribute("request_id", id)
+		}
+		w.Header().Set(TraceparentHeader, span.Context().Traceparent())
+
+		inner.ServeHTTP(w, r.WithContext(ctx))
+
+		code := http.StatusOK
+		if mw, ok := w.(interface{ Status() int }); ok {
+			code = mw.Status()
+		}
+		span.SetAttribute("http.status_code", strconv.Itoa(code))
+		if code >= http.StatusInternalServerError {
+			span.SetError(fmt.Errorf("%d %s", code, http.StatusText(code)))
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often full buckets are forgotten
+const pruneInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket is full again, so it can be forgotten
+	full time.Time
+}
+
+// MemoryStore keep token buckets in memory
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]bucket
+	lastPrune time.Time
+}
+
+// NewMemoryStore return an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]bucket{}}
+}
+
+// Take a token of the bucket of key at now
+func (s *MemoryStore) Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = bucket{tokens: float64(p.Burst), last: now}
+	}
+	rate := p.rate()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(p.Burst), b.tokens+elapsed.Seconds()*rate)
+		b.last = now
+	}
+
+	res := Result{Limit: p.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((float64(p.Burst) - b.tokens) / rate)
+	b.full = now.Add(res.Reset)
+	s.buckets[key] = b
+	return res, nil
+}
+
+// prune forget full buckets, at most once per interval
+func (s *MemoryStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	s.lastPrune = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// rate return the tokens added per second
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
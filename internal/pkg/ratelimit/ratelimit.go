@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"booking/configs"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StoreMemory keep buckets in the process, each instance limits on its own
+	StoreMemory = "memory"
+)
+
+const (
+	// KeyIP limit each client IP
+	KeyIP = "ip"
+	// KeyMember limit each member of the JWT, requests without token are limited by IP
+	KeyMember = "member"
+	// KeyAPIKey limit each API key, requests without a known key are limited by IP
+	KeyAPIKey = "api_key"
+)
+
+// Policy is a token bucket holding up to Burst tokens and refilled with Limit tokens every
+// Period, a request takes a token. Key tells what the requests are counted by.
+type Policy struct {
+	Name   string
+	Key    string
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// Result of taking a token
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is the time before a token is available, zero if allowed
+	RetryAfter time.Duration
+	// Reset is the time before the bucket is full again
+	Reset time.Duration
+}
+
+// Store keep the buckets, a store shared by instances makes limits apply to the whole service
+type Store interface {
+	// Take a token of the bucket of key at now
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error)
+}
+
+// Limiter apply named policies
+type Limiter struct {
+	store        Store
+	policies     map[string]Policy
+	routes       []route
+	apiKeyHeader string
+	apiKeys      map[string]bool
+}
+
+// route bind a path, or the paths starting with it when prefix, to a policy
+type route struct {
+	path   string
+	prefix bool
+	policy string
+}
+
+// New return a limiter of the configured policies and store
+func New(conf configs.RateLimit) (*Limiter, error) {
+	var store Store
+	switch conf.Store {
+	case "", StoreMemory:
+		store = NewMemoryStore()
+	default:
+		return nil, errors.Errorf("rate limit store not supported: %s", conf.Store)
+	}
+	return NewLimiter(store, conf)
+}
+
+// NewLimiter return a limiter keeping its buckets in store
+func NewLimiter(store Store, conf configs.RateLimit) (*Limiter, error) {
+	l := &Limiter{
+		store:        store,
+		policies:     map[string]Policy{},
+		apiKeyHeader: conf.APIKeyHeader,
+		apiKeys:      map[string]bool{},
+	}
+	if l.apiKeyHeader == "" {
+		l.apiKeyHeader = "X-API-Key"
+	}
+	for _, digest := range conf.APIKeys {
+		if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+			return nil, errors.Errorf("rate limit api key %q: not a SHA-256 hex digest", digest)
+		}
+		l.apiKeys[strings.ToLower(digest)] = true
+	}
+	for name, c := range conf.Policies {
+		p := Policy{Name: name, Key: c.Key, Limit: c.Limit, Period: c.Period, Burst: c.Burst}
+		if p.Key == "" {
+			p.Key = KeyIP
+		}
+		switch p.Key {
+		case KeyIP, KeyMember, KeyAPIKey:
+		default:
+			return nil, errors.Errorf("rate limit policy %s: key not supported: %s", name, p.Key)
+		}
+		if p.Limit <= 0 || p.Period <= 0 {
+			return nil, errors.Errorf("rate limit policy %s: limit and period must be positive", name)
+		}
+		if p.Burst <= 0 {
+			p.Burst = p.Limit
+		}
+		l.policies[name] = p
+	}
+	for _, c := range conf.Routes {
+		rt := route{path: strings.TrimSuffix(c.Path, "*"), policy: c.Policy}
+		rt.prefix = rt.path != c.Path
+		if !strings.HasPrefix(rt.path, "/") || strings.Contains(rt.path, "*") {
+			return nil, errors.Errorf("rate limit route %q: path must start with / and may only end with *", c.Path)
+		}
+		if _, ok := l.policies[rt.policy]; !ok {
+			return nil, errors.Errorf("rate limit route %q: unknown policy %s", c.Path, c.Policy)
+		}
+		l.routes = append(l.routes, rt)
+	}
+	return l, nil
+}
+
+// Policy return the policy of given name, false if it is not configured
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	p, ok := l.policies[name]
+	return p, ok
+}
+
+// RoutePolicy return the policy of the route path, false if no route matches.
+// Exact paths win over prefixes of the same length.
+func (l *Limiter) RoutePolicy(path string) (Policy, bool) {
+	var match *route
+	for i, rt := range l.routes {
+		if rt.prefix && !strings.HasPrefix(path, rt.path) || !rt.prefix && path != rt.path {
+			continue
+		}
+		if match == nil || len(rt.path) > len(match.path) || len(rt.path) == len(match.path) && !rt.prefix {
+			match = &l.routes[i]
+		}
+	}
+	if match == nil {
+		return Policy{}, false
+	}
+	return l.policies[match.policy], true
+}
+
+// APIKeyHeader return the header carrying API keys
+func (l *Limiter) APIKeyHeader() string {
+	return l.apiKeyHeader
+}
+
+// APIKey return the digest identifying key, false if key is not one of the configured API keys
+func (l *Limiter) APIKey(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(key))
+	digest := hex.EncodeToString(sum[:])
+	return digest, l.apiKeys[digest]
+}
+
+// Take a token of the bucket of key for policy p
+func (l *Limiter) Take(ctx context.Context, p Policy, key string) (Result, error) {
+	return l.store.Take(ctx, p.Name+":"+key, p, time.Now())
+}
@@ -0,0 +1,172 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"booking/configs"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	s := NewMemoryStore()
+	p := Policy{Name: "test", Limit: 1, Period: time.Second, Burst: 3}
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for i := 2; i >= 0; i-- {
+		res, _ := s.Take(ctx, "a", p, now)
+		if !res.Allowed || res.Remaining != i || res.Limit != 3 {
+			t.Fatalf("Take() = %+v, want allowed with %d remaining", res, i)
+		}
+	}
+	res, _ := s.Take(ctx, "a", p, now)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Fatalf("Take() on empty bucket = %+v, want retry after 1s", res)
+	}
+
+	// other keys have their own bucket
+	if res, _ := s.Take(ctx, "b", p, now); !res.Allowed {
+		t.Errorf("Take() of another key = %+v, want allowed", res)
+	}
+
+	res, _ = s.Take(ctx, "a", p, now.Add(500*time.Millisecond))
+	if res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Take() after 500ms = %+v, want retry after 500ms", res)
+	}
+	if res, _ = s.Take(ctx, "a", p, now.Add(time.Second)); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("Take() after refill = %+v, want allowed", res)
+	}
+
+	// a bucket never holds more than burst
+	if res, _ = s.Take(ctx, "a", p, now.Add(time.Hour)); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("Take() after an hour = %+v, want 2 remaining", res)
+	}
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	s := NewMemoryStore()
+	p := Policy{Name: "test", Limit: 1, Period: time.Second, Burst: 1}
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Take(context.Background(), "a", p, now)
+	s.Take(context.Background(), "b", p, now.Add(2*time.Minute))
+	if _, ok := s.buckets["a"]; ok || len(s.buckets) != 1 {
+		t.Errorf("buckets = %v, want full bucket forgotten", s.buckets)
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	conf := configs.RateLimit{Policies: map[string]configs.RateLimitPolicy{
+		"login": {Limit: 5, Period: time.Minute},
+	}}
+	l, err := New(conf)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	p, ok := l.Policy("login")
+	if !ok || p.Key != KeyIP || p.Burst != 5 {
+		t.Errorf("Policy() = %+v, want defaults", p)
+	}
+	if _, ok := l.Policy("api"); ok {
+		t.Error("Policy() of a policy not configured is found")
+	}
+	if l.APIKeyHeader() != "X-API-Key" {
+		t.Errorf("APIKeyHeader() = %s", l.APIKeyHeader())
+	}
+
+	invalid := []configs.RateLimit{
+		{Store: "redis"},
+		{Policies: map[string]configs.RateLimitPolicy{"a": {Key: "email", Limit: 1, Period: time.Second}}},
+		{Policies: map[string]configs.RateLimitPolicy{"a": {Limit: 1}}},
+	}
+	for _, c := range invalid {
+		if _, err := New(c); err == nil {
+			t.Errorf("New(%+v) error = nil", c)
+		}
+	}
+}
+
+func TestRoutePolicy(t *testing.T) {
+	policies := map[string]configs.RateLimitPolicy{}
+	for _, name := range []string{"login", "api", "restaurant", "restaurants"} {
+		policies[name] = configs.RateLimitPolicy{Limit: 1, Period: time.Second}
+	}
+	l, err := New(configs.RateLimit{Policies: policies, Routes: []configs.RateLimitRoute{
+		{Path: "/login", Policy: "login"},
+		{Path: "/auth/2fa/*", Policy: "api"},
+		{Path: "/auth/2fa/login", Policy: "login"},
+		{Path: "/api/v1/*", Policy: "api"},
+		{Path: "/api/v1/restaurant*", Policy: "restaurant"},
+		{Path: "/api/v1/restaurant", Policy: "restaurants"},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		policy string
+		ok     bool
+	}{
+		{"/login", "login", true},
+		{"/login/other", "", false},
+		{"/auth/2fa/enroll", "api", true},
+		{"/auth/2fa/login", "login", true},
+		{"/api/v1/member/{id:[a-z0-9-\\-]+}", "api", true},
+		{"/api/v1/restaurant/{id}", "restaurant", true},
+		{"/api/v1/restaurant", "restaurants", true},
+		{"/metrics", "", false},
+	}
+	for _, tt := range tests {
+		policy, ok := l.RoutePolicy(tt.path)
+		if policy.Name != tt.policy || ok != tt.ok {
+			t.Errorf("RoutePolicy(%s) = %s, %v; expected %s, %v", tt.path, policy.Name, ok, tt.policy, tt.ok)
+		}
+	}
+
+	invalid := [][]configs.RateLimitRoute{
+		{{Path: "login", Policy: "login"}},
+		{{Path: "/api/*/member", Policy: "api"}},
+		{{Path: "/login"}},
+		{{Path: "/login", Policy: "logn"}},
+	}
+	for _, routes := range invalid {
+		if _, err := New(configs.RateLimit{Policies: policies, Routes: routes}); err == nil {
+			t.Errorf("New(%+v) error = nil", routes)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	sum := sha256.Sum256([]byte("secret-key"))
+	digest := hex.EncodeToString(sum[:])
+	// digests are accepted in upper case too
+	l, err := New(configs.RateLimit{APIKeys: []string{strings.ToUpper(digest)}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		key string
+		ok  bool
+	}{
+		{"secret-key", true},
+		{"other-key", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		got, ok := l.APIKey(tt.key)
+		if ok != tt.ok || ok && got != digest {
+			t.Errorf("APIKey(%q) = %s, %v; expected %v", tt.key, got, ok, tt.ok)
+		}
+	}
+
+	for _, invalid := range []string{"secret-key", digest[:32]} {
+		if _, err := New(configs.RateLimit{APIKeys: []string{invalid}}); err == nil {
+			t.Errorf("New() with api key %q error = nil", invalid)
+		}
+	}
+}
@@ -4,16 +4,21 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"booking/configs"
+	"booking/internal/pkg/apperr"
+
 	"github.com/pkg/errors"
 )
 
-// JSON write status and JSON data to http response writer
+// JSON write status and JSON data to http response writer, the error messages
+// in data depend on the Accept-Language header so caches are told to vary on it
 func JSON(w http.ResponseWriter, status int, data interface{}) {
 	b, err := json.Marshal(data)
 	if err != nil {
 		Error(w, errors.Wrap(err, "json marshal failed"), http.StatusInternalServerError)
 		return
 	}
+	w.Header().Add("Vary", "Accept-Language")
 	w.WriteHeader(status)
 	w.Write(b)
 }
@@ -22,3 +27,40 @@ func JSON(w http.ResponseWriter, status int, data interface{}) {
 func Error(w http.ResponseWriter, err error, status int) {
 	http.Error(w, err.Error(), status)
 }
+
+// Err write the HTTP status and the error code of the kind of err, the code of the domain error
+// in errors.en.yml is preferred, errors without kind are internal server errors
+func Err(w http.ResponseWriter, em *configs.ErrorMessage, err error) {
+	status, code := kindStatus(em, apperr.KindOf(err))
+	if e, ok := apperr.As(err); ok && e.Code != "" {
+		if ec := em.ErrorCode(e.Code); ec.HasError() {
+			code = ec
+		}
+	}
+	JSON(w, status, code)
+}
+
+func kindStatus(em *configs.ErrorMessage, kind apperr.Kind) (int, configs.ErrorCode) {
+	switch kind {
+	case apperr.NotFound:
+		return http.StatusNotFound, em.Database.DataNotFound
+	case apperr.Conflict:
+		return http.StatusConflict, em.Conflict.DataExists
+	case apperr.Unauthorized:
+		return http.StatusUnauthorized, em.InvalidValue.FailedAuthentication
+	case apperr.Forbidden:
+		return http.StatusForbidden, em.InvalidValue.PermissionDenied
+	case apperr.Validation:
+		return http.StatusBadRequest, em.InvalidValue.ValidationFailed
+	case apperr.Unavailable:
+		return http.StatusServiceUnavailable, em.Database.Database
+	case apperr.TooManyRequests:
+		return http.StatusTooManyRequests, em.TooManyRequests.RateLimited
+	}
+	return http.StatusInternalServerError, em.InvalidValue.Request
+}
+
+// Lang return the error messages in the language of the Accept-Language header of r
+func Lang(r *http.Request, em *configs.ErrorMessage) *configs.ErrorMessage {
+	return em.Lang(r.Header.Get("Accept-Language"))
+}
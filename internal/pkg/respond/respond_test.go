@@ -0,0 +1,95 @@
+package respond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"booking/configs"
+	"booking/internal/pkg/apperr"
+)
+
+func errorMessage(t *testing.T) *configs.ErrorMessage {
+	em := &configs.ErrorMessage{ConfigPath: "../../../configs"}
+	if err := em.Init(); err != nil {
+		t.Fatalf("Init() err = %v", err)
+	}
+	return em
+}
+
+func TestErr(t *testing.T) {
+	em := errorMessage(t)
+	notFound := apperr.New(apperr.NotFound, "database.data_not_found", "not found")
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   configs.ErrorCode
+	}{
+		{"not found", notFound, http.StatusNotFound, em.Database.DataNotFound},
+		{"wrapped", fmt.Errorf("find member: %w", notFound), http.StatusNotFound, em.Database.DataNotFound},
+		{"code of the error", apperr.New(apperr.Conflict, "conflict.reservation_overlap", "overlap"), http.StatusConflict, em.Conflict.ReservationOverlap},
+		{"code of the kind", apperr.New(apperr.Conflict, "", "duplicate"), http.StatusConflict, em.Conflict.DataExists},
+		{"unknown code", apperr.New(apperr.Forbidden, "invalid_value.missing", "forbidden"), http.StatusForbidden, em.InvalidValue.PermissionDenied},
+		{"unauthorized", apperr.New(apperr.Unauthorized, "", "unauthorized"), http.StatusUnauthorized, em.InvalidValue.FailedAuthentication},
+		{"validation", apperr.New(apperr.Validation, "invalid_value.invalid_token", "invalid"), http.StatusBadRequest, em.InvalidValue.InvalidToken},
+		{"unavailable", apperr.Wrap(apperr.Unavailable, "database.database", errors.New("connection refused")), http.StatusServiceUnavailable, em.Database.Database},
+		{"too many requests", apperr.New(apperr.TooManyRequests, "too_many_requests.account_locked", "locked"), http.StatusTooManyRequests, em.TooManyRequests.AccountLocked},
+		{"without kind", errors.New("boom"), http.StatusInternalServerError, em.InvalidValue.Request},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.code.HasError() {
+				t.Fatalf("code %+v is missing in errors.en.yml", tt.code)
+			}
+			w := httptest.NewRecorder()
+			Err(w, em, tt.err)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			var code configs.ErrorCode
+			if err := json.Unmarshal(w.Body.Bytes(), &code); err != nil {
+				t.Fatalf("Unmarshal() err = %v", err)
+			}
+			if code != tt.code {
+				t.Errorf("code = %+v, want %+v", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestLang(t *testing.T) {
+	em := errorMessage(t)
+	tests := []struct {
+		acceptLanguage string
+		lang           string
+	}{
+		{"", "en"},
+		{"vi-VN,vi;q=0.9,en;q=0.8", "vi"},
+		{"ja", "en"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Language", tt.acceptLanguage)
+		w := httptest.NewRecorder()
+		Err(w, Lang(r, em), apperr.New(apperr.NotFound, "database.data_not_found", "not found"))
+
+		var code configs.ErrorCode
+		if err := json.Unmarshal(w.Body.Bytes(), &code); err != nil {
+			t.Fatalf("Unmarshal() err = %v", err)
+		}
+		want := em.Lang(tt.lang).Database.DataNotFound
+		if code != want || code.Code != em.Database.DataNotFound.Code {
+			t.Errorf("Accept-Language %q code = %+v; expected %+v", tt.acceptLanguage, code, want)
+		}
+		if vary := w.Header().Get("Vary"); vary != "Accept-Language" {
+			t.Errorf("Vary = %q; expected Accept-Language", vary)
+		}
+	}
+	if vi, en := em.Lang("vi").Database.DataNotFound.Message, em.Database.DataNotFound.Message; vi == en {
+		t.Errorf("vi message = %q; expected a translation", vi)
+	}
+}
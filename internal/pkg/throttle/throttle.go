@@ -0,0 +1,149 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	"booking/configs"
+)
+
+// Policy decide how long to wait after consecutive failed attempts. Each failure doubles the
+// delay from BaseDelay up to MaxDelay, reaching MaxAttempts locks for Lockout. Failures are
+// forgotten once Window passed since the last one but a lockout always lasts Lockout,
+// a zero MaxAttempts never locks.
+type Policy struct {
+	MaxAttempts int
+	Window      time.Duration
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Lockout     time.Duration
+}
+
+// NewPolicy return the policy of the given config
+func NewPolicy(c configs.Throttle) Policy {
+	return Policy{
+		MaxAttempts: c.MaxAttempts,
+		Window:      c.Window,
+		BaseDelay:   c.BaseDelay,
+		MaxDelay:    c.MaxDelay,
+		Lockout:     c.Lockout,
+	}
+}
+
+// Delay return the time to wait after the given number of consecutive failures
+func (p Policy) Delay(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	if p.Locks(failures) {
+		return p.Lockout
+	}
+	delay := p.BaseDelay
+	for i := 1; i < failures && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Locks return true if the number of failures reaches the lockout threshold
+func (p Policy) Locks(failures int) bool {
+	return p.MaxAttempts > 0 && failures >= p.MaxAttempts
+}
+
+// Failures return the number of failures still counted at now, the last one happened at last
+func (p Policy) Failures(failures int, last, now time.Time) int {
+	if p.Window > 0 && now.Sub(last) > p.Window {
+		return 0
+	}
+	return failures
+}
+
+// Locked return true if the failures reached the lockout threshold and the lockout started at
+// last is not over at now. The lockout lasts Lockout even when it is longer than Window
+func (p Policy) Locked(failures int, last, now time.Time) bool {
+	return p.Locks(failures) && now.Before(last.Add(p.Lockout))
+}
+
+// Wait return how long to wait from now before the next attempt, zero if it is allowed
+func (p Policy) Wait(failures int, last, now time.Time) time.Duration {
+	if p.Locked(failures, last, now) {
+		return last.Add(p.Lockout).Sub(now)
+	}
+	wait := last.Add(p.Delay(p.Failures(failures, last, now))).Sub(now)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+type entry struct {
+	failures int
+	last     time.Time
+}
+
+// Tracker count failed attempts per key in memory, e.g. per client IP
+type Tracker struct {
+	mu        sync.Mutex
+	policy    Policy
+	entries   map[string]entry
+	lastPrune time.Time
+}
+
+// NewTracker return a new tracker applying the given policy
+func NewTracker(p Policy) *Tracker {
+	return &Tracker{
+		policy:  p,
+		entries: map[string]entry{},
+	}
+}
+
+// Wait return how long the key must wait from now before the next attempt
+func (t *Tracker) Wait(key string, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entries[key]
+	return t.policy.Wait(e.failures, e.last, now)
+}
+
+// Locked return true if the key reached the lockout threshold and still waits
+func (t *Tracker) Locked(key string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entries[key]
+	return t.policy.Locked(e.failures, e.last, now)
+}
+
+// Fail record a failed attempt of the key at now and return the number of counted failures
+func (t *Tracker) Fail(key string, now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(now)
+	e := t.entries[key]
+	e.failures = t.policy.Failures(e.failures, e.last, now) + 1
+	e.last = now
+	t.entries[key] = e
+	return e.failures
+}
+
+// Reset forget the failures of the key
+func (t *Tracker) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, key)
+}
+
+// prune forget keys without counted failures, at most once per window
+func (t *Tracker) prune(now time.Time) {
+	if t.policy.Window <= 0 || now.Sub(t.lastPrune) < t.policy.Window {
+		return
+	}
+	t.lastPrune = now
+	for key, e := range t.entries {
+		if t.policy.Failures(e.failures, e.last, now) == 0 && t.policy.Wait(e.failures, e.last, now) == 0 {
+			delete(t.entries, key)
+		}
+	}
+}
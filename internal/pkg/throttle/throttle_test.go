@@ -0,0 +1,109 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+)
+
+var policy = Policy{
+	MaxAttempts: 5,
+	Window:      time.Hour,
+	BaseDelay:   time.Second,
+	MaxDelay:    4 * time.Second,
+	Lockout:     15 * time.Minute,
+}
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 4 * time.Second},
+		{5, 15 * time.Minute},
+		{9, 15 * time.Minute},
+	}
+	for _, tc := range tests {
+		if got := policy.Delay(tc.failures); got != tc.want {
+			t.Errorf("Delay(%d) = %v; expected %v", tc.failures, got, tc.want)
+		}
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(policy)
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+
+	if wait := tracker.Wait("ip", now); wait != 0 {
+		t.Fatalf("Wait() before failures = %v; expected 0", wait)
+	}
+	for i := 1; i <= 4; i++ {
+		if failures := tracker.Fail("ip", now); failures != i {
+			t.Fatalf("Fail() = %d; expected %d", failures, i)
+		}
+	}
+	if wait := tracker.Wait("ip", now.Add(time.Second)); wait != 3*time.Second {
+		t.Errorf("Wait() after 4 failures = %v; expected 3s", wait)
+	}
+	if tracker.Locked("ip", now) {
+		t.Errorf("Locked() after 4 failures; expected only delayed")
+	}
+	if wait := tracker.Wait("other", now); wait != 0 {
+		t.Errorf("Wait() of another key = %v; expected 0", wait)
+	}
+
+	tracker.Fail("ip", now)
+	if !tracker.Locked("ip", now.Add(time.Minute)) {
+		t.Errorf("Locked() after 5 failures = false; expected true")
+	}
+	if tracker.Locked("ip", now.Add(15*time.Minute)) {
+		t.Errorf("Locked() after lockout = true; expected false")
+	}
+
+	if failures := tracker.Fail("ip", now.Add(2*time.Hour)); failures != 1 {
+		t.Errorf("Fail() after window = %d; expected failures to be forgotten", failures)
+	}
+	tracker.Reset("ip")
+	if wait := tracker.Wait("ip", now.Add(2*time.Hour)); wait != 0 {
+		t.Errorf("Wait() after Reset() = %v; expected 0", wait)
+	}
+}
+
+func TestLockoutLongerThanWindow(t *testing.T) {
+	p := Policy{MaxAttempts: 3, Window: 10 * time.Minute, BaseDelay: time.Second, Lockout: time.Hour}
+	tracker := NewTracker(p)
+	now := time.Date(2022, 12, 24, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		tracker.Fail("ip", now)
+	}
+
+	tests := []struct {
+		after  time.Duration
+		wait   time.Duration
+		locked bool
+	}{
+		{time.Minute, 59 * time.Minute, true},
+		{30 * time.Minute, 30 * time.Minute, true},
+		{59 * time.Minute, time.Minute, true},
+		{time.Hour, 0, false},
+		{2 * time.Hour, 0, false},
+	}
+	for _, tc := range tests {
+		at := now.Add(tc.after)
+		if wait := p.Wait(3, now, at); wait != tc.wait {
+			t.Errorf("Wait() %v after lockout = %v; expected %v", tc.after, wait, tc.wait)
+		}
+		if locked := tracker.Locked("ip", at); locked != tc.locked {
+			t.Errorf("Locked() %v after lockout = %v; expected %v", tc.after, locked, tc.locked)
+		}
+	}
+
+	// pruning after the window keeps the locked key
+	tracker.Fail("other", now.Add(30*time.Minute))
+	if wait := tracker.Wait("ip", now.Add(30*time.Minute)); wait != 30*time.Minute {
+		t.Errorf("Wait() after prune = %v; expected 30m", wait)
+	}
+}
@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the key "12345678901234567890" of the RFC 4226 and RFC 6238 test vectors
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 appendix D
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	key, _ := decode(rfcSecret)
+	for counter, code := range want {
+		if got := hotp(key, uint64(counter), 6); got != code {
+			t.Errorf("hotp(%d) = %s; expected %s", counter, got, code)
+		}
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	// RFC 6238 appendix B, SHA-1 codes truncated to 8 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	key, _ := decode(rfcSecret)
+	for _, tc := range tests {
+		if got := hotp(key, uint64(Step(time.Unix(tc.unix, 0))), 8); got != tc.want {
+			t.Errorf("code at %d = %s; expected %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "005924" {
+		t.Fatalf("Code() = %s; expected 005924", code)
+	}
+
+	if step, ok := Validate(rfcSecret, code, now, 1); !ok || step != Step(now) {
+		t.Errorf("Validate() = %d, %v; expected %d, true", step, ok, Step(now))
+	}
+	if _, ok := Validate(rfcSecret, code, now.Add(Period), 1); !ok {
+		t.Errorf("Validate() one step later = false; expected true")
+	}
+	if _, ok := Validate(rfcSecret, code, now.Add(2*Period), 1); ok {
+		t.Errorf("Validate() two steps later = true; expected false")
+	}
+	if _, ok := Validate(rfcSecret, "000000", now, 1); ok {
+		t.Errorf("Validate() of wrong code = true; expected false")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Booking", "staff@booking.local", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Booking:staff@booking.local?") || !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") {
+		t.Errorf("URI() = %s", uri)
+	}
+}
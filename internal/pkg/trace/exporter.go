@@ -0,0 +1,132 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"booking/configs"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Exporter send ended spans to a tracing backend
+type Exporter interface {
+	Export(span SpanData)
+	// Shutdown flush pending spans until ctx is done and release the exporter
+	Shutdown(ctx context.Context) error
+}
+
+var (
+	exporterMu sync.RWMutex
+	exporter   Exporter = noopExporter{}
+)
+
+// New return the exporter selected by the tracing configuration
+func New(conf configs.Tracing) (Exporter, error) {
+	switch conf.Exporter {
+	case "", ExporterNone:
+		return noopExporter{}, nil
+	case ExporterStdout:
+		return NewWriterExporter(os.Stdout), nil
+	case ExporterFile:
+		return NewFileExporter(conf.File)
+	case ExporterOTLP:
+		return NewOTLPExporter(conf), nil
+	}
+	return nil, errors.Errorf("trace exporter not supported: %s", conf.Exporter)
+}
+
+// SetExporter set the exporter of ended spans
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	exporter = e
+	exporterMu.Unlock()
+}
+
+// CurrentExporter return the exporter of ended spans
+func CurrentExporter() Exporter {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+	return exporter
+}
+
+type noopExporter struct{}
+
+func (noopExporter) Export(SpanData)                {}
+func (noopExporter) Shutdown(context.Context) error { return nil }
+
+// WriterExporter write spans as JSON lines, for local use
+type WriterExporter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+type jsonSpan struct {
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Name         string            `json:"name"`
+	Kind         Kind              `json:"kind"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	DurationMS   float64           `json:"duration_ms"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{
+		w:   w,
+		enc: json.NewEncoder(w),
+	}
+}
+
+// NewFileExporter return an exporter appending spans to the file at path
+func NewFileExporter(path string) (*WriterExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open trace file")
+	}
+	return NewWriterExporter(f), nil
+}
+
+// Export write the span as a JSON line
+func (e *WriterExporter) Export(span SpanData) {
+	s := jsonSpan{
+		TraceID:    span.Context.TraceID.String(),
+		SpanID:     span.Context.SpanID.String(),
+		Name:       span.Name,
+		Kind:       span.Kind,
+		Start:      span.StartTime,
+		End:        span.EndTime,
+		DurationMS: float64(span.EndTime.Sub(span.StartTime).Microseconds()) / 1000,
+		Attributes: span.Attributes,
+		Error:      span.Error,
+	}
+	if span.ParentID.IsValid() {
+		s.ParentSpanID = span.ParentID.String()
+	}
+	e.mu.Lock()
+	e.enc.Encode(s)
+	e.mu.Unlock()
+}
+
+// Shutdown close the underlying file
+func (e *WriterExporter) Shutdown(ctx context.Context) error {
+	if f, ok := e.w.(*os.File); ok && f != os.Stdout && f != os.Stderr {
+		return f.Close()
+	}
+	return nil
+}
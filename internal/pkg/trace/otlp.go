@@ -0,0 +1,232 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"booking/configs"
+)
+
+const (
+	defaultBatchSize = 512
+	// maxQueueSize bound the spans kept while the collector is unreachable, newer spans are dropped
+	maxQueueSize = 4096
+)
+
+// OTLPExporter send spans in batches to an OpenTelemetry collector with OTLP/HTTP JSON,
+// a batch is sent when full or every interval
+type OTLPExporter struct {
+	url         string
+	headers     map[string]string
+	serviceName string
+	batchSize   int
+	interval    time.Duration
+	client      *http.Client
+
+	mu      sync.Mutex
+	pending []SpanData
+	flush   chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewOTLPExporter return an exporter to the collector at conf.OTLP.Endpoint, e.g.
+// http://localhost:4318, and start sending batches in the background
+func NewOTLPExporter(conf configs.Tracing) *OTLPExporter {
+	e := &OTLPExporter{
+		url:         strings.TrimSuffix(conf.OTLP.Endpoint, "/") + "/v1/traces",
+		headers:     conf.OTLP.Headers,
+		serviceName: conf.ServiceName,
+		batchSize:   conf.OTLP.BatchSize,
+		interval:    conf.OTLP.Interval,
+		client:      &http.Client{Timeout: conf.OTLP.Timeout},
+		flush:       make(chan struct{}, 1),
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	if e.serviceName == "" {
+		e.serviceName = "booking"
+	}
+	if e.batchSize <= 0 {
+		e.batchSize = defaultBatchSize
+	}
+	if e.interval <= 0 {
+		e.interval = 5 * time.Second
+	}
+	if e.client.Timeout <= 0 {
+		e.client.Timeout = 10 * time.Second
+	}
+	go e.run()
+	return e
+}
+
+// Export queue the span for the next batch
+func (e *OTLPExporter) Export(span SpanData) {
+	e.mu.Lock()
+	if len(e.pending) < maxQueueSize {
+		e.pending = append(e.pending, span)
+	}
+	full := len(e.pending) >= e.batchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Shutdown send the pending spans and stop the exporter
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	close(e.done)
+	select {
+	case <-e.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *OTLPExporter) run() {
+	defer close(e.stopped)
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-e.flush:
+		case <-e.done:
+			e.send()
+			return
+		}
+		e.send()
+	}
+}
+
+// send post the pending spans in batches, failed batches are dropped
+func (e *OTLPExporter) send() {
+	e.mu.Lock()
+	spans := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	for len(spans) > 0 {
+		n := e.batchSize
+		if n > len(spans) {
+			n = len(spans)
+		}
+		if err := e.post(spans[:n]); err != nil {
+			log.Printf("trace: failed to export %d spans, err: %v", n, err)
+		}
+		spans = spans[n:]
+	}
+}
+
+func (e *OTLPExporter) post(spans []SpanData) error {
+	body, err := json.Marshal(otlpRequest(e.serviceName, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector answered %s", resp.Status)
+	}
+	return nil
+}
+
+type (
+	otlpKeyValue struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	}
+
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              Kind           `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+)
+
+// otlpRequest build the ExportTraceServiceRequest of spans in the OTLP JSON encoding
+func otlpRequest(serviceName string, spans []SpanData) map[string]interface{} {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.Context.TraceID.String(),
+			SpanID:            span.Context.SpanID.String(),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        attributes(span.Attributes),
+		}
+		if span.ParentID.IsValid() {
+			s.ParentSpanID = span.ParentID.String()
+		}
+		// status codes are 0 unset and 2 error
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: 2, Message: span.Error}
+		}
+		otlpSpans = append(otlpSpans, s)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": attributes(map[string]string{"service.name": serviceName}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "booking"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func attributes(m map[string]string) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(m))
+	for k, v := range m {
+		kv := otlpKeyValue{Key: k}
+		kv.Value.StringValue = v
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
@@ -0,0 +1,199 @@
+// Package trace record spans of requests, propagated with W3C traceparent headers,
+// and export them to an Exporter
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// TraceID identify a trace across services
+	TraceID [16]byte
+	// SpanID identify a span of a trace
+	SpanID [8]byte
+
+	// SpanContext is the part of a span propagated to other services
+	SpanContext struct {
+		TraceID TraceID
+		SpanID  SpanID
+		Sampled bool
+	}
+
+	// SpanData is a timed operation of a trace, e.g. a request, a service or a database call
+	SpanData struct {
+		Name       string
+		Context    SpanContext
+		ParentID   SpanID
+		Kind       Kind
+		StartTime  time.Time
+		EndTime    time.Time
+		Attributes map[string]string
+		// Error is the message of the error failing the operation, empty on success
+		Error string
+	}
+
+	// Span is a span in progress, exported once ended
+	Span struct {
+		mu    sync.Mutex
+		ended bool
+		data  SpanData
+	}
+
+	// Kind tells the role of a span in a trace
+	Kind int
+
+	spanKey   struct{}
+	remoteKey struct{}
+)
+
+// Span kinds, numbered as in OTLP
+const (
+	KindInternal Kind = 1
+	KindServer   Kind = 2
+	KindClient   Kind = 3
+)
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+func (id SpanID) String() string  { return hex.EncodeToString(id[:]) }
+
+// IsValid tells if the trace id is not all zeros
+func (id TraceID) IsValid() bool { return id != TraceID{} }
+
+// IsValid tells if the span id is not all zeros
+func (id SpanID) IsValid() bool { return id != SpanID{} }
+
+// ParseTraceparent parse a W3C traceparent header, e.g.
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceparent(header string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || parts[0] == "ff" {
+		return sc, false
+	}
+	// version 00 has exactly 4 fields, later versions may append fields
+	if parts[0] == "00" && len(parts) != 4 {
+		return sc, false
+	}
+	_, ok := decodeHex(parts[0], 1)
+	flags, ok2 := decodeHex(parts[3], 1)
+	traceID, ok3 := decodeHex(parts[1], len(sc.TraceID))
+	spanID, ok4 := decodeHex(parts[2], len(sc.SpanID))
+	if !ok || !ok2 || !ok3 || !ok4 {
+		return sc, false
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&1 == 1
+	return sc, sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// decodeHex decode lowercase hex of n bytes
+func decodeHex(s string, n int) ([]byte, bool) {
+	if len(s) != 2*n || strings.ToLower(s) != s {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// Traceparent format the span context as a W3C traceparent header
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+// WithRemote return a context with the span context received from another service,
+// spans started from it are its children
+func WithRemote(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// FromContext return the span of the context
+func FromContext(ctx context.Context) (*Span, bool) {
+	span, ok := ctx.Value(spanKey{}).(*Span)
+	return span, ok
+}
+
+// Start a span with given name, child of the span or remote span context of ctx,
+// and return a context holding it. The span must be ended with End.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal)
+}
+
+// StartKind start a span of given kind, see Start
+func StartKind(ctx context.Context, name string, kind Kind) (context.Context, *Span) {
+	data := SpanData{
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: map[string]string{},
+	}
+	if parent, ok := FromContext(ctx); ok {
+		data.Context.TraceID = parent.data.Context.TraceID
+		data.Context.Sampled = parent.data.Context.Sampled
+		data.ParentID = parent.data.Context.SpanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok {
+		data.Context.TraceID = remote.TraceID
+		data.Context.Sampled = remote.Sampled
+		data.ParentID = remote.SpanID
+	} else {
+		rand.Read(data.Context.TraceID[:])
+		data.Context.Sampled = true
+	}
+	rand.Read(data.Context.SpanID[:])
+
+	span := &Span{data: data}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Context return the span context to propagate to other services
+func (s *Span) Context() SpanContext {
+	return s.data.Context
+}
+
+// SetAttribute set an attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	s.mu.Lock()
+	s.data.Attributes[key] = value
+	s.mu.Unlock()
+}
+
+// SetError mark the span as failed by err, nil errors are ignored
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Error = err.Error()
+	s.mu.Unlock()
+}
+
+// End the span and export it if sampled, later calls do nothing
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	data.Attributes = make(map[string]string, len(s.data.Attributes))
+	for k, v := range s.data.Attributes {
+		data.Attributes[k] = v
+	}
+	s.mu.Unlock()
+
+	if data.Context.Sampled {
+		CurrentExporter().Export(data)
+	}
+}
@@ -0,0 +1,188 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"booking/configs"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (r *recorder) Export(span SpanData) {
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+}
+
+func (r *recorder) Shutdown(ctx context.Context) error { return nil }
+
+func record(t *testing.T) *recorder {
+	r := &recorder{}
+	previous := CurrentExporter()
+	SetExporter(r)
+	t.Cleanup(func() { SetExporter(previous) })
+	return r
+}
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		header  string
+		ok      bool
+		sampled bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		// later versions may append fields
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, true},
+		{"", false, false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", false, false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+	}
+	for _, tt := range tests {
+		sc, ok := ParseTraceparent(tt.header)
+		if ok != tt.ok {
+			t.Errorf("ParseTraceparent(%q) ok = %v, want %v", tt.header, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if sc.Sampled != tt.sampled {
+			t.Errorf("ParseTraceparent(%q) sampled = %v, want %v", tt.header, sc.Sampled, tt.sampled)
+		}
+		if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" {
+			t.Errorf("ParseTraceparent(%q) = %s %s", tt.header, sc.TraceID, sc.SpanID)
+		}
+	}
+
+	sc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if got := sc.Traceparent(); got != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("Traceparent() = %s", got)
+	}
+}
+
+func TestStart(t *testing.T) {
+	r := record(t)
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, server := StartKind(WithRemote(context.Background(), remote), "server", KindServer)
+	_, child := Start(ctx, "child")
+	child.SetAttribute("key", "value")
+	child.SetError(errors.New("failed"))
+	child.End()
+	child.End()
+	server.End()
+
+	if len(r.spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(r.spans))
+	}
+	c, s := r.spans[0], r.spans[1]
+	if s.Context.TraceID != remote.TraceID || s.ParentID != remote.SpanID || s.Kind != KindServer {
+		t.Errorf("server span = %+v, want child of remote %+v", s, remote)
+	}
+	if c.Context.TraceID != remote.TraceID || c.ParentID != s.Context.SpanID {
+		t.Errorf("child span = %+v, want child of server span", c)
+	}
+	if c.Attributes["key"] != "value" || c.Error != "failed" {
+		t.Errorf("child span attributes = %v, error = %q", c.Attributes, c.Error)
+	}
+
+	_, root := Start(context.Background(), "root")
+	root.End()
+	if rs := r.spans[2]; !rs.Context.TraceID.IsValid() || rs.Context.TraceID == remote.TraceID || rs.ParentID.IsValid() {
+		t.Errorf("root span = %+v, want a new trace", rs)
+	}
+
+	unsampled, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	_, span := Start(WithRemote(context.Background(), unsampled), "unsampled")
+	span.End()
+	if len(r.spans) != 3 {
+		t.Errorf("exported %d spans, want unsampled span dropped", len(r.spans))
+	}
+}
+
+func TestWriterExporter(t *testing.T) {
+	var b bytes.Buffer
+	e := NewWriterExporter(&b)
+	start := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	sc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.Export(SpanData{
+		Context:    sc,
+		Name:       "member.Login",
+		Kind:       KindInternal,
+		StartTime:  start,
+		EndTime:    start.Add(1500 * time.Microsecond),
+		Attributes: map[string]string{"k": "v"},
+	})
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", b.String(), err)
+	}
+	if got["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || got["span_id"] != "00f067aa0ba902b7" ||
+		got["name"] != "member.Login" || got["duration_ms"] != 1.5 {
+		t.Errorf("exported %v", got)
+	}
+	if _, ok := got["parent_span_id"]; ok {
+		t.Errorf("root span exported with a parent: %v", got)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var mu sync.Mutex
+	var requests []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Authorization") != "token" {
+			t.Errorf("request to %s with headers %v", r.URL.Path, r.Header)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	conf := configs.Tracing{ServiceName: "booking-test"}
+	conf.OTLP.Endpoint = srv.URL
+	conf.OTLP.Headers = map[string]string{"Authorization": "token"}
+	conf.OTLP.Interval = time.Hour
+	e := NewOTLPExporter(conf)
+
+	_, span := Start(context.Background(), "span")
+	span.SetError(errors.New("failed"))
+	span.End()
+	e.Export(span.data)
+	if err := e.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want spans flushed on shutdown", len(requests))
+	}
+	b, _ := json.Marshal(requests[0])
+	for _, want := range []string{`"booking-test"`, `"name":"span"`, `"traceId":"` + span.Context().TraceID.String() + `"`, `"message":"failed"`} {
+		if !bytes.Contains(b, []byte(want)) {
+			t.Errorf("request %s does not contain %s", b, want)
+		}
+	}
+}
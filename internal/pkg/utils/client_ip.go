@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// TrustedProxies are the networks of the proxies in front of the server, e.g. the gateway or
+// the load balancer, whose X-Forwarded-For and X-Real-IP headers are trusted
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies return the trusted proxies of IP addresses or CIDR networks
+func ParseTrustedProxies(addrs []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(addrs))
+	for _, addr := range addrs {
+		if !strings.Contains(addr, "/") {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", addr)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %v", addr, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// Trusts return true if the IP address is one of a trusted proxy
+func (t TrustedProxies) Trusts(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range t {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP return the IP address of the client sending the request. Forwarded headers are only
+// read when the request comes from a trusted proxy: X-Forwarded-For is walked from the right,
+// the address added by the nearest proxy, to the first one that is not a trusted proxy.
+// X-Real-IP is used when there is no X-Forwarded-For.
+func (t TrustedProxies) ClientIP(r *http.Request) string {
+	ip := remoteIP(r)
+	if !t.Trusts(ip) {
+		return ip
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	if len(hops) > 0 {
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			ip = hop
+			if !t.Trusts(hop) {
+				break
+			}
+		}
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return ip
+}
+
+// WithClientIP return a copy of ctx holding the IP address of the client
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP return the IP address of the client sending the request, resolved by the ClientIP
+// middleware behind trusted proxies, the peer address otherwise
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// remoteIP return the IP address of the peer of the connection
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{"direct client", "203.0.113.7:5123", nil, "", "203.0.113.7"},
+		{"direct client forging headers", "203.0.113.7:5123", []string{"198.51.100.1"}, "198.51.100.2", "203.0.113.7"},
+		{"behind proxy", "10.1.2.3:443", []string{"198.51.100.1"}, "", "198.51.100.1"},
+		{"behind proxies", "10.1.2.3:443", []string{"198.51.100.1, 192.168.1.1"}, "", "198.51.100.1"},
+		{"forged first hop", "10.1.2.3:443", []string{"1.2.3.4, 198.51.100.1"}, "", "198.51.100.1"},
+		{"several headers", "10.1.2.3:443", []string{"1.2.3.4", "198.51.100.1, 10.0.0.5"}, "", "198.51.100.1"},
+		{"invalid hop", "10.1.2.3:443", []string{"198.51.100.1, bogus, 10.0.0.5"}, "", "10.0.0.5"},
+		{"only proxies", "10.1.2.3:443", []string{"10.0.0.9"}, "", "10.0.0.9"},
+		{"real ip", "192.168.1.1:443", nil, "198.51.100.1", "198.51.100.1"},
+		{"invalid real ip", "192.168.1.1:443", nil, "bogus", "192.168.1.1"},
+		{"ipv6 proxy", "[fd00::1]:443", []string{"2001:db8::1"}, "", "2001:db8::1"},
+		{"untrusted neighbour", "192.168.1.2:443", []string{"198.51.100.1"}, "", "192.168.1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, forwarded := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			if got := proxies.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %s; expected %s", got, tt.want)
+			}
+			if got := ClientIP(r.WithContext(WithClientIP(r.Context(), tt.want))); got != tt.want {
+				t.Errorf("ClientIP() of the context = %s; expected %s", got, tt.want)
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/login", nil)
+	r.RemoteAddr = "10.1.2.3:443"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+	if got := ClientIP(r); got != "10.1.2.3" {
+		t.Errorf("ClientIP() without middleware = %s; expected the peer address", got)
+	}
+
+	for _, invalid := range []string{"bogus", "10.0.0.0/33"} {
+		if _, err := ParseTrustedProxies([]string{invalid}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded", invalid)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"booking/configs"
+	"booking/internal/pkg/utils"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError tells why a field of a request is invalid, Field is its JSON name and
+// Rule and Param the failed validate tag, e.g. max and 60
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors is the response of an invalid request
+type Errors struct {
+	configs.ErrorCode
+	Errors []FieldError `json:"errors"`
+}
+
+// New return a validator naming fields by their JSON name
+func New() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return utils.Underscore(f.Name)
+		}
+		return name
+	})
+	return v
+}
+
+// Response return the validation failed error of err with the fields failing validation,
+// JSON values of the wrong type fail the type rule and other errors have no fields
+func Response(em *configs.ErrorMessage, err error) Errors {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Invalid(em, FieldError{Field: typeErr.Field, Rule: "type", Param: typeErr.Type.String()})
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return Invalid(em)
+	}
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		f := FieldError{
+			Field: field(fe),
+			Rule:  fe.Tag(),
+			Param: fe.Param(),
+		}
+		// rules comparing with other fields have their Go name as param
+		if strings.HasSuffix(f.Rule, "field") || strings.HasPrefix(f.Rule, "required_with") {
+			f.Param = utils.Underscore(f.Param)
+		}
+		f.Message = message(em, f, fe.Kind())
+		fields = append(fields, f)
+	}
+	return Errors{ErrorCode: em.InvalidValue.ValidationFailed, Errors: fields}
+}
+
+// Invalid return the validation failed error of the given fields, for values checked
+// by handlers such as query parameters. Empty messages are set from the rule
+func Invalid(em *configs.ErrorMessage, fields ...FieldError) Errors {
+	for i := range fields {
+		if fields[i].Message == "" {
+			fields[i].Message = message(em, fields[i], reflect.Invalid)
+		}
+	}
+	if fields == nil {
+		fields = []FieldError{}
+	}
+	return Errors{ErrorCode: em.InvalidValue.ValidationFailed, Errors: fields}
+}
+
+// field return the JSON path of the field without the request type, e.g. items[0].name
+func field(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// message return the message of the rule with {field} and {param} replaced. Rules on lengths
+// of strings, slices and maps may have their own message named <rule>_length
+func message(em *configs.ErrorMessage, f FieldError, kind reflect.Kind) string {
+	msg := ""
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		msg = em.Validation[f.Rule+"_length"]
+	}
+	if msg == "" {
+		msg = em.Validation[f.Rule]
+	}
+	if msg == "" {
+		msg = em.Validation["default"]
+	}
+	return strings.NewReplacer("{field}", f.Field, "{param}", f.Param).Replace(msg)
+}
@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"booking/configs"
+)
+
+var em = func() *configs.ErrorMessage {
+	em := &configs.ErrorMessage{}
+	em.InvalidValue.ValidationFailed = configs.ErrorCode{Code: "502", Message: "Form validation errors."}
+	em.Validation = map[string]string{
+		"default":    "{field} is invalid.",
+		"required":   "{field} is required.",
+		"max":        "{field} must be at most {param}.",
+		"max_length": "{field} must be at most {param} characters long.",
+		"gtfield":    "{field} must be after {param}.",
+		"type":       "{field} must be of type {param}.",
+	}
+	return em
+}()
+
+type request struct {
+	Name      string    `json:"name" validate:"required,max=3"`
+	Slots     int       `json:"slots" validate:"max=10"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time" validate:"gtfield=StartTime"`
+	Email     string    `validate:"email"`
+	Items     []item    `json:"items" validate:"dive"`
+}
+
+type item struct {
+	Note string `json:"note" validate:"required"`
+}
+
+func TestResponse(t *testing.T) {
+	now := time.Now()
+	req := request{
+		Name:      "abcd",
+		Slots:     11,
+		StartTime: now,
+		EndTime:   now,
+		Email:     "x",
+		Items:     []item{{Note: "a"}, {}},
+	}
+	got := Response(em, New().Struct(req))
+	want := Errors{
+		ErrorCode: em.InvalidValue.ValidationFailed,
+		Errors: []FieldError{
+			{Field: "name", Rule: "max", Param: "3", Message: "name must be at most 3 characters long."},
+			{Field: "slots", Rule: "max", Param: "10", Message: "slots must be at most 10."},
+			{Field: "end_time", Rule: "gtfield", Param: "start_time", Message: "end_time must be after start_time."},
+			{Field: "email", Rule: "email", Message: "email is invalid."},
+			{Field: "items[1].note", Rule: "required", Message: "items[1].note is required."},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Response() = %+v, want %+v", got, want)
+	}
+
+	b, _ := json.Marshal(got)
+	if !strings.HasPrefix(string(b), `{"code":"502","message":"Form validation errors.","errors":[{"field":"name","rule":"max","param":"3"`) {
+		t.Errorf("JSON = %s", b)
+	}
+}
+
+func TestResponseOfDecodeError(t *testing.T) {
+	var req request
+	err := json.Unmarshal([]byte(`{"slots":"many"}`), &req)
+	got := Response(em, err)
+	want := []FieldError{{Field: "slots", Rule: "type", Param: "int", Message: "slots must be of type int."}}
+	if !reflect.DeepEqual(got.Errors, want) {
+		t.Errorf("Response().Errors = %+v, want %+v", got.Errors, want)
+	}
+
+	got = Response(em, json.Unmarshal([]byte(`{`), &req))
+	if got.Code != "502" || got.Errors == nil || len(got.Errors) != 0 {
+		t.Errorf("Response() of a syntax error = %+v, want no fields", got)
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"time"
 
 	"booking/internal/app/api"
+	"booking/internal/app/db"
+	"booking/internal/app/migrations"
 	config "booking/configs"
 	envconfig "booking/internal/pkg/config/env"
 	"booking/internal/pkg/glog"
@@ -19,9 +22,11 @@ func main() {
 	logger := glog.New()
 	stage := flag.String("stage", "dev", "set working environment")
 	configPath := flag.String("config", "configs", "set configs path, default as: 'configs'")
+	lang := flag.String("lang", config.DefaultLanguage, "set default language of error messages, default as: 'en'")
+	flag.Parse()
 
 	// error message
-	em := config.ErrorMessage{ConfigPath: *configPath}
+	em := config.ErrorMessage{ConfigPath: *configPath, DefaultLanguage: *lang}
 	if err := em.Init(); err != nil {
 		logger.Errorf("failed to load error messages, err: %v", err)
 	}
@@ -42,8 +47,26 @@ func main() {
 	if mongoConf.Database != "" {
 		conf.Database.Mongo.Database = mongoConf.Database
 	}
+
+	if mongoConf.CollectionPrefix != "" {
+		conf.Database.Mongo.CollectionPrefix = mongoConf.CollectionPrefix
+	}
+
+	if mongoConf.Username != "" {
+		conf.Database.Mongo.Username = mongoConf.Username
+		conf.Database.Mongo.Password = mongoConf.Password
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := migrate(conf, flag.Args()[1:], logger); err != nil {
+			logger.Errorf("migrate failed, err: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger.Infof("initializing HTTP routing...")
-	router, err := api.Init(conf, em)
+	router, infra, err := api.Init(conf, em)
 	if err != nil {
 		logger.Panicf("failed to init routing, err: %v", err)
 	}
@@ -76,6 +99,12 @@ func main() {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt, os.Kill)
 	<-signals
+	// stop receiving traffic from load balancers while requests in progress finish
+	health.NotReady()
+	if drain := conf.HTTPServer.ShutdownDrain; drain > 0 {
+		logger.Infof("draining traffic for %v...", drain)
+		time.Sleep(drain)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), conf.HTTPServer.ShutdownTimeout)
 	defer cancel()
 	logger.Infof("shutting down http server...")
@@ -83,4 +112,23 @@ func main() {
 		logger.Errorf("http server shutdown with error: %v", err)
 	}
 	// shutdown background services goes here
+	logger.Infof("closing database connections and flushing spans...")
+	if err := infra.Close(ctx); err != nil {
+		logger.Errorf("infrastructure connections closed with error: %v", err)
+	}
+}
+
+// migrate run the migrate command against the MongoDB database
+func migrate(conf *config.Configs, args []string, logger glog.Logger) error {
+	if conf.Database.Type != db.TypeMongoDB {
+		return fmt.Errorf("migrations only apply to mongodb, %s tables are created on start", conf.Database.Type)
+	}
+	database, err := db.ConnectMongo(context.Background(), conf.Database.Mongo, logger)
+	if err != nil {
+		return err
+	}
+	defer database.Disconnect(context.Background())
+
+	migrator := migrations.New(database, logger)
+	return migrations.Run(context.Background(), migrator, args, os.Stdout)
 }